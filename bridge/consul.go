@@ -0,0 +1,232 @@
+// Package bridge mirrors the Eureka registry into other service catalogs, so
+// that services migrating away from Eureka can discover the ones still
+// registered to it.
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abetobing/go-eureka/eureka"
+)
+
+const defaultSyncInterval = 30 * time.Second
+
+// ConsulBridge registers the instances of the Eureka registry to the catalog
+// of a Consul agent, one Consul service per Eureka application, named after
+// the application in lower case. The Eureka status of an instance is carried
+// over as the status of a check: passing for UP, critical for DOWN and
+// OUT_OF_SERVICE, warning otherwise.
+type ConsulBridge struct {
+	r          *eureka.Registry
+	consulAddr string
+	client     *http.Client
+
+	mu     sync.Mutex
+	synced map[string]consulService
+}
+
+// consulService is an instance registered to Consul by the bridge.
+type consulService struct {
+	node string
+	id   string
+}
+
+// NewConsulBridge returns a bridge from the registry of eurekaClient to the
+// Consul HTTP API at consulAddr, e.g. "http://localhost:8500".
+func NewConsulBridge(eurekaClient *eureka.Registry, consulAddr string) *ConsulBridge {
+	return &ConsulBridge{
+		r:          eurekaClient,
+		consulAddr: strings.TrimSuffix(consulAddr, "/"),
+		client:     &http.Client{Timeout: 10 * time.Second},
+		synced:     make(map[string]consulService),
+	}
+}
+
+// Sync fetches the registry from Eureka once and registers every instance to
+// Consul. Instances registered by a previous Sync that are gone from Eureka
+// are deregistered from Consul. The instances registered before a failure
+// are still deregistered by a later Sync once gone from Eureka.
+func (b *ConsulBridge) Sync(ctx context.Context) error {
+	apps, err := b.r.GetApplications(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := make(map[string]consulService)
+	defer func() {
+		for id, service := range current {
+			b.synced[id] = service
+		}
+	}()
+	for _, app := range apps.Application {
+		for _, instance := range app.Instance {
+			registration, err := consulRegistration(app.Name, instance)
+			if err != nil {
+				b.r.Logger().Println(fmt.Errorf("Cannot mirror instance %s to Consul. %v", instance.InstanceId, err))
+				continue
+			}
+			if err := b.put(ctx, "register", registration); err != nil {
+				return err
+			}
+			current[instance.InstanceId] = consulService{node: registration.Node, id: registration.Service.ID}
+		}
+	}
+
+	for id, service := range b.synced {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		deregistration := consulDeregistration{Node: service.node, ServiceID: service.id}
+		if err := b.put(ctx, "deregister", deregistration); err != nil {
+			return err
+		}
+		delete(b.synced, id)
+	}
+	return nil
+}
+
+// StartSync calls Sync every interval, 30 seconds when zero, logging the
+// failures. It blocks until ctx is done, then returns ctx.Err().
+func (b *ConsulBridge) StartSync(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultSyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := b.Sync(ctx); err != nil && ctx.Err() == nil {
+			b.r.Logger().Println(fmt.Errorf("Cannot sync Eureka to Consul. %v", err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// put sends body to the catalog endpoint of Consul.
+func (b *ConsulBridge) put(ctx context.Context, endpoint string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("Cannot marshal Consul %s body. %v", endpoint, err)
+	}
+	url := fmt.Sprintf("%s/v1/catalog/%s", b.consulAddr, endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Cannot reach Consul. %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Consul catalog %s FAILED with status %v", endpoint, resp.Status)
+	}
+	return nil
+}
+
+type consulCatalogRegistration struct {
+	Node    string
+	Address string
+	Service consulAgentService
+	Check   consulCheck
+}
+
+type consulAgentService struct {
+	ID      string
+	Service string
+	Address string
+	Port    int
+	Meta    map[string]string `json:",omitempty"`
+}
+
+type consulCheck struct {
+	Node      string
+	CheckID   string
+	Name      string
+	Status    string
+	ServiceID string
+}
+
+type consulDeregistration struct {
+	Node      string
+	ServiceID string
+}
+
+// consulRegistration returns the catalog registration of an instance of the
+// Eureka application appName.
+func consulRegistration(appName string, instance eureka.InstanceDetails) (consulCatalogRegistration, error) {
+	port, err := instance.Port.IntPort()
+	if err != nil {
+		return consulCatalogRegistration{}, err
+	}
+	node := instance.HostName
+	if node == "" {
+		node = instance.IpAddr
+	}
+
+	meta := make(map[string]string, len(instance.Metadata))
+	for k, v := range instance.Metadata {
+		if k == "@class" {
+			continue
+		}
+		meta[consulMetaKey(k)] = v
+	}
+
+	return consulCatalogRegistration{
+		Node:    node,
+		Address: instance.IpAddr,
+		Service: consulAgentService{
+			ID:      instance.InstanceId,
+			Service: strings.ToLower(appName),
+			Address: instance.IpAddr,
+			Port:    port,
+			Meta:    meta,
+		},
+		Check: consulCheck{
+			Node:      node,
+			CheckID:   "eureka:" + instance.InstanceId,
+			Name:      "Eureka status",
+			Status:    consulStatus(instance.Status),
+			ServiceID: instance.InstanceId,
+		},
+	}, nil
+}
+
+func consulStatus(status string) string {
+	switch eureka.InstanceStatus(status) {
+	case eureka.StatusUp:
+		return "passing"
+	case eureka.StatusDown, eureka.StatusOutOfService:
+		return "critical"
+	default:
+		return "warning"
+	}
+}
+
+// consulMetaKey replaces the characters Consul does not allow in metadata
+// keys, such as the dots of namespaced Eureka keys, with underscores.
+func consulMetaKey(key string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-':
+			return c
+		}
+		return '_'
+	}, key)
+}
@@ -0,0 +1,124 @@
+package bridge_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abetobing/go-eureka/bridge"
+	"github.com/abetobing/go-eureka/eureka"
+	"github.com/abetobing/go-eureka/server"
+)
+
+// consulStub records the catalog registrations and deregistrations sent to
+// it, failing the registrations of the services in failing.
+type consulStub struct {
+	mu           sync.Mutex
+	failing      map[string]bool
+	registered   []string
+	deregistered []string
+}
+
+func (s *consulStub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Service   struct{ ID, Service string }
+		ServiceID string
+	}
+	if req.Method != http.MethodPut || json.NewDecoder(req.Body).Decode(&body) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch req.URL.Path {
+	case "/v1/catalog/register":
+		if s.failing[body.Service.Service] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.registered = append(s.registered, body.Service.ID)
+	case "/v1/catalog/deregister":
+		s.deregistered = append(s.deregistered, body.ServiceID)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *consulStub) calls() (registered, deregistered []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	registered, deregistered = s.registered, s.deregistered
+	s.registered, s.deregistered = nil, nil
+	return registered, deregistered
+}
+
+// startInstance registers an instance of appName to the Eureka server at
+// eurekaUrl.
+func startInstance(t *testing.T, eurekaUrl, appName string) *eureka.Registry {
+	t.Helper()
+	r, err := eureka.NewEureka(eurekaUrl, appName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestConsulBridgeSync(t *testing.T) {
+	ctx := context.Background()
+	eurekaServer := httptest.NewServer(server.NewEurekaServer())
+	defer eurekaServer.Close()
+	stub := &consulStub{failing: map[string]bool{}}
+	consul := httptest.NewServer(stub)
+	defer consul.Close()
+
+	orders := startInstance(t, eurekaServer.URL, "ORDERS")
+	defer orders.Stop(ctx)
+	payments := startInstance(t, eurekaServer.URL, "PAYMENTS")
+	b := bridge.NewConsulBridge(orders, consul.URL)
+
+	assertSync(t, b, stub, false, []string{orders.InstanceId, payments.InstanceId}, nil)
+	payments.Stop(ctx)
+	assertSync(t, b, stub, false, []string{orders.InstanceId}, []string{payments.InstanceId})
+}
+
+func TestConsulBridgeSyncTracksFailedPass(t *testing.T) {
+	ctx := context.Background()
+	eurekaServer := httptest.NewServer(server.NewEurekaServer())
+	defer eurekaServer.Close()
+	stub := &consulStub{failing: map[string]bool{"payments": true}}
+	consul := httptest.NewServer(stub)
+	defer consul.Close()
+
+	orders := startInstance(t, eurekaServer.URL, "ORDERS")
+	payments := startInstance(t, eurekaServer.URL, "PAYMENTS")
+	defer payments.Stop(ctx)
+	b := bridge.NewConsulBridge(payments, consul.URL)
+
+	// ORDERS is registered to Consul before the pass fails on PAYMENTS.
+	assertSync(t, b, stub, true, []string{orders.InstanceId}, nil)
+	delete(stub.failing, "payments")
+	orders.Stop(ctx)
+	assertSync(t, b, stub, false, []string{payments.InstanceId}, []string{orders.InstanceId})
+}
+
+func assertSync(t *testing.T, b *bridge.ConsulBridge, stub *consulStub, wantErr bool, wantRegistered, wantDeregistered []string) {
+	t.Helper()
+	if err := b.Sync(context.Background()); (err != nil) != wantErr {
+		t.Fatalf("Sync() error = %v, want error %v", err, wantErr)
+	}
+	registered, deregistered := stub.calls()
+	if strings.Join(registered, ",") != strings.Join(wantRegistered, ",") {
+		t.Errorf("registered %v, want %v", registered, wantRegistered)
+	}
+	if strings.Join(deregistered, ",") != strings.Join(wantDeregistered, ",") {
+		t.Errorf("deregistered %v, want %v", deregistered, wantDeregistered)
+	}
+}
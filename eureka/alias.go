@@ -0,0 +1,141 @@
+package eureka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AddAlias registers the instance under a second app name, with its current
+// status. From then on heartbeats, status changes and deregistration apply
+// to the alias as well; failures for an alias are logged rather than
+// returned.
+func (r *Registry) AddAlias(ctx context.Context, aliasAppName string) error {
+	alias := r.normalizeAppName(aliasAppName)
+	if alias == r.AppName {
+		return fmt.Errorf("Alias %s is the app name of the instance", alias)
+	}
+	status := r.Status()
+	if status == "" {
+		status = StatusStarting
+	}
+	if err := r.sendAliasStatus(ctx, alias, status); err != nil {
+		return err
+	}
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	for _, existing := range r.state.aliases {
+		if existing == alias {
+			return nil
+		}
+	}
+	r.state.aliases = append(r.state.aliases, alias)
+	return nil
+}
+
+// RemoveAlias deregisters the instance from the alias only.
+func (r *Registry) RemoveAlias(ctx context.Context, aliasAppName string) error {
+	alias := r.normalizeAppName(aliasAppName)
+	r.state.mu.Lock()
+	for i, existing := range r.state.aliases {
+		if existing == alias {
+			r.state.aliases = append(r.state.aliases[:i:i], r.state.aliases[i+1:]...)
+			break
+		}
+	}
+	r.state.mu.Unlock()
+
+	return r.deregisterAlias(ctx, alias)
+}
+
+// Aliases returns the app names added with AddAlias.
+func (r *Registry) Aliases() []string {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return append([]string(nil), r.state.aliases...)
+}
+
+// sendAliasStatus (re-)registers the instance under the alias with the given
+// status.
+func (r *Registry) sendAliasStatus(ctx context.Context, alias string, status InstanceStatus) error {
+	requestBody := r.buildBody(string(status))
+	requestBody.Instance.App = alias
+	requestBody.Instance.VipAddress = strings.ToLower(alias)
+	secureVipAddress := strings.ToLower(alias)
+	requestBody.Instance.SecureVipAddress = &secureVipAddress
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		return fmt.Errorf("Cannot marshal instance body. %v", err)
+	}
+
+	url := fmt.Sprintf("%s/apps/%s", r.CurrentZone(), alias)
+	resp, err := r.postRequest(ctx, url, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 || resp.StatusCode == 200 {
+		return nil
+	}
+	return newEurekaError("Alias registration", resp)
+}
+
+func (r *Registry) deregisterAlias(ctx context.Context, alias string) error {
+	url := fmt.Sprintf("%s/apps/%s/%s", r.CurrentZone(), alias, r.InstanceId)
+	resp, err := r.deleteRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 || resp.StatusCode == 200 {
+		return nil
+	}
+	return newEurekaError("Alias deregistration", resp)
+}
+
+// sendAliasesStatus reports the status to every alias.
+func (r *Registry) sendAliasesStatus(ctx context.Context, status InstanceStatus) {
+	for _, alias := range r.Aliases() {
+		if err := r.sendAliasStatus(ctx, alias, status); err != nil {
+			r.logger.Printf("Cannot update status '%s' of alias %s. %v\n", status, alias, err)
+		}
+	}
+}
+
+// renewAliases sends a heartbeat for every alias, registering the alias
+// again when Eureka does not know it.
+func (r *Registry) renewAliases(ctx context.Context) {
+	for _, alias := range r.Aliases() {
+		url := fmt.Sprintf("%s/apps/%s/%s", r.CurrentZone(), alias, r.InstanceId)
+		resp, err := r.putRequest(ctx, url)
+		if err != nil {
+			r.logger.Printf("Cannot send heartbeat for alias %s. %v\n", alias, err)
+			continue
+		}
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == 204 || resp.StatusCode == 200:
+		case resp.StatusCode == 404:
+			if err := r.sendAliasStatus(ctx, alias, r.Status()); err != nil {
+				r.logger.Printf("Cannot register alias %s again. %v\n", alias, err)
+			}
+		default:
+			r.logger.Println(newEurekaError(fmt.Sprintf("Heartbeat for alias %s", alias), resp))
+		}
+	}
+}
+
+// deregisterAliases deregisters every alias, keeping them for a later
+// registration.
+func (r *Registry) deregisterAliases(ctx context.Context) {
+	for _, alias := range r.Aliases() {
+		if err := r.deregisterAlias(ctx, alias); err != nil {
+			r.logger.Printf("Cannot deregister alias %s. %v\n", alias, err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package eureka
+
+// Application is a single application as returned by Eureka, holding every
+// instance registered under its name.
+type Application struct {
+	Name     string            `json:"name"`
+	Instance []InstanceDetails `json:"instance"`
+}
+
+// Applications is the full registry as returned by GET /apps.
+type Applications struct {
+	VersionsDelta string        `json:"versions__delta"`
+	AppsHashcode  string        `json:"apps__hashcode"`
+	Application   []Application `json:"application"`
+}
+
+// ApplicationsResponse is the envelope Eureka wraps around Applications.
+type ApplicationsResponse struct {
+	Applications Applications `json:"applications"`
+}
+
+// ApplicationResponse is the envelope Eureka wraps around a single Application.
+type ApplicationResponse struct {
+	Application Application `json:"application"`
+}
+
+// InstanceResponse is the envelope Eureka wraps around a single instance.
+type InstanceResponse struct {
+	Instance InstanceDetails `json:"instance"`
+}
@@ -0,0 +1,25 @@
+package eureka
+
+import "os"
+
+const asgMetadataKey = "asg"
+
+// WithASGNameFromEnv sets InitOptions.ASGName from the AWS_AUTO_SCALING_GROUP
+// or, failing that, the EC2_AUTOSCALING_GROUPNAME environment variable. It
+// leaves the ASG name alone when neither is set.
+func WithASGNameFromEnv() Option {
+	return func(r *Registry) error {
+		for _, key := range []string{"AWS_AUTO_SCALING_GROUP", "EC2_AUTOSCALING_GROUPNAME"} {
+			if name := os.Getenv(key); name != "" {
+				r.opt.ASGName = name
+				break
+			}
+		}
+		return nil
+	}
+}
+
+// instanceASG returns the auto scaling group instance registered with.
+func (r *Registry) instanceASG(instance *InstanceDetails) string {
+	return r.instanceMetadata(instance)[asgMetadataKey]
+}
@@ -0,0 +1,34 @@
+package eureka
+
+import (
+	"crypto/tls"
+	"errors"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// WithAutoTLS registers the instance with its secure port 443 enabled and
+// makes ServingTLSConfig return the TLS config of manager, so the service
+// serves certificates obtained and renewed by autocert, e.g. from Let's
+// Encrypt. Renewed certificates are picked up without registering again,
+// since the config fetches them through its GetCertificate callback.
+func WithAutoTLS(manager *autocert.Manager) Option {
+	return func(r *Registry) error {
+		if manager == nil {
+			return errors.New("WithAutoTLS needs an autocert manager")
+		}
+		r.opt.ServingTLSConfig = manager.TLSConfig()
+		r.opt.SecurePortEnabled = true
+		r.opt.SecurePort = "443"
+		return nil
+	}
+}
+
+// ServingTLSConfig returns InitOptions.ServingTLSConfig, for the http.Server
+// of the service to listen on the secure port with:
+//
+//	srv := &http.Server{Addr: ":443", TLSConfig: r.ServingTLSConfig()}
+//	srv.ListenAndServeTLS("", "")
+func (r *Registry) ServingTLSConfig() *tls.Config {
+	return r.opt.ServingTLSConfig
+}
@@ -0,0 +1,168 @@
+package eureka
+
+import (
+	"context"
+	"math/rand"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// BalancerOptions configures a Balancer.
+type BalancerOptions struct {
+	// Strategy picks among the UP instances. Defaults to round robin.
+	Strategy LoadBalancingStrategy
+
+	// Cache is where instances are looked up. When nil, the balancer keeps
+	// its own cache, refreshed every CacheTTL.
+	Cache    *RegistryCache
+	CacheTTL time.Duration
+
+	// MinHealthyInstanceCount makes Pick fail with ErrBelowThreshold when
+	// fewer UP instances are available, rather than overloading the few
+	// survivors.
+	MinHealthyInstanceCount int
+
+	// PeerAffinity makes Pick prefer the instances on the same host as the
+	// Eureka server the registry talks to, as told by the base URL of their
+	// home page, sparing requests the hop to a distant peer.
+	PeerAffinity bool
+
+	// PreferSameASG makes Pick prefer the instances registered with the same
+	// InitOptions.ASGName as the registry, when it has one.
+	PreferSameASG bool
+}
+
+// Balancer picks instances of an application to send requests to.
+type Balancer struct {
+	r       *Registry
+	appName string
+	cache   *RegistryCache
+	opts    BalancerOptions
+}
+
+func NewBalancer(r *Registry, appName string, opts BalancerOptions) *Balancer {
+	if opts.Strategy == nil {
+		opts.Strategy = NewRoundRobinStrategy()
+	}
+	cache := opts.Cache
+	if cache == nil {
+		cache = NewRegistryCache(r, opts.CacheTTL)
+	}
+	return &Balancer{r: r, appName: appName, cache: cache, opts: opts}
+}
+
+// Pick returns one of the UP instances of the application. Sandbox
+// instances are picked when preferSandbox is set and any is UP, production
+// instances otherwise.
+func (b *Balancer) Pick(ctx context.Context, preferSandbox bool) (*InstanceDetails, error) {
+	instances, err := b.cache.GetHealthyInstances(ctx, b.appName)
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) < b.opts.MinHealthyInstanceCount {
+		return nil, ErrBelowThreshold
+	}
+
+	var sandbox, production []InstanceDetails
+	for _, instance := range instances {
+		if b.r.isSandbox(&instance) {
+			sandbox = append(sandbox, instance)
+		} else {
+			production = append(production, instance)
+		}
+	}
+	instances = production
+	if preferSandbox && len(sandbox) > 0 {
+		instances = sandbox
+	}
+
+	if b.opts.PeerAffinity {
+		instances = b.peerInstances(instances)
+	}
+	if b.opts.PreferSameASG {
+		instances = b.sameASGInstances(instances)
+	}
+	if b.r.preferSameZone() {
+		instances = b.r.sameZoneInstances(instances)
+	}
+
+	instance := b.opts.Strategy.Select(instances)
+	if instance == nil {
+		return nil, ErrNoInstances
+	}
+	return instance, nil
+}
+
+// LoadBalancingStrategy picks one instance out of a non-empty set of
+// candidates.
+type LoadBalancingStrategy interface {
+	Select(instances []InstanceDetails) *InstanceDetails
+}
+
+// RoundRobinStrategy cycles through the candidates in order.
+type RoundRobinStrategy struct {
+	next uint64
+}
+
+func NewRoundRobinStrategy() *RoundRobinStrategy {
+	return &RoundRobinStrategy{}
+}
+
+func (s *RoundRobinStrategy) Select(instances []InstanceDetails) *InstanceDetails {
+	if len(instances) == 0 {
+		return nil
+	}
+	n := atomic.AddUint64(&s.next, 1) - 1
+	return &instances[n%uint64(len(instances))]
+}
+
+// RandomStrategy picks a candidate uniformly at random.
+type RandomStrategy struct{}
+
+func (RandomStrategy) Select(instances []InstanceDetails) *InstanceDetails {
+	if len(instances) == 0 {
+		return nil
+	}
+	return &instances[rand.Intn(len(instances))]
+}
+
+// peerInstances returns the instances on the same host as the Eureka server,
+// or all of them when there is none.
+func (b *Balancer) peerInstances(instances []InstanceDetails) []InstanceDetails {
+	zone, err := url.Parse(b.r.CurrentZone())
+	if err != nil {
+		return instances
+	}
+	var peers []InstanceDetails
+	for _, instance := range instances {
+		homePage, err := url.Parse(instance.HomePageUrl)
+		if err == nil && strings.EqualFold(homePage.Hostname(), zone.Hostname()) {
+			peers = append(peers, instance)
+		}
+	}
+	if len(peers) == 0 {
+		return instances
+	}
+	return peers
+}
+
+// sameASGInstances returns the instances in the auto scaling group of the
+// registry, or all of them when there is none.
+func (b *Balancer) sameASGInstances(instances []InstanceDetails) []InstanceDetails {
+	asg := b.r.opt.ASGName
+	if asg == "" {
+		return instances
+	}
+	var same []InstanceDetails
+	for _, instance := range instances {
+		if b.r.instanceASG(&instance) == asg {
+			same = append(same, instance)
+		}
+	}
+	if len(same) == 0 {
+		return instances
+	}
+	return same
+}
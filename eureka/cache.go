@@ -0,0 +1,183 @@
+package eureka
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultCacheTTL = 30 * time.Second
+
+// RegistryCache keeps a local copy of the Eureka registry, fetching it again
+// once it is older than the TTL. When a refresh fails the stale copy keeps
+// being served, so lookups survive a Eureka outage.
+//
+// Refreshes honor the HTTP caching headers of Eureka: the registry is fetched
+// with If-None-Match when an ETag was received, keeping the local copy on
+// 304 Not Modified, and a Cache-Control max-age longer than the TTL extends
+// the time the copy is considered fresh.
+type RegistryCache struct {
+	r   *Registry
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	apps    map[string]Application
+	fetched time.Time
+	etag    string
+	maxAge  time.Duration
+}
+
+// NewRegistryCache returns a cache over the registry r. A zero ttl defaults
+// to 30 seconds, the default registry fetch interval of Eureka clients.
+func NewRegistryCache(r *Registry, ttl time.Duration) *RegistryCache {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	return &RegistryCache{r: r, ttl: ttl}
+}
+
+// Refresh fetches the full registry from Eureka.
+func (c *RegistryCache) Refresh(ctx context.Context) error {
+	c.mu.RLock()
+	etag := c.etag
+	if c.apps == nil {
+		etag = ""
+	}
+	c.mu.RUnlock()
+
+	apps, header, err := c.r.getApplications(ctx, etag)
+	if err != nil {
+		return err
+	}
+	maxAge, noStore := parseCacheControl(header.Get("Cache-Control"))
+	if apps == nil {
+		c.mu.Lock()
+		c.fetched = time.Now()
+		c.maxAge = maxAge
+		c.mu.Unlock()
+		return nil
+	}
+
+	byName := make(map[string]Application, len(apps.Application))
+	for _, app := range apps.Application {
+		byName[c.r.normalizeAppName(app.Name)] = app
+	}
+
+	c.mu.Lock()
+	for name, app := range byName {
+		byName[name] = resolveConflicts(c.apps[name], app)
+	}
+	c.apps = byName
+	c.fetched = time.Now()
+	c.maxAge = maxAge
+	c.etag = header.Get("ETag")
+	if noStore {
+		c.etag = ""
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// GetApplication returns the cached application, refreshing the cache first
+// when it has expired.
+func (c *RegistryCache) GetApplication(ctx context.Context, appName string) (*Application, error) {
+	if err := c.refreshIfStale(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	app, ok := c.apps[c.r.normalizeAppName(appName)]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, ErrApplicationNotFound
+	}
+	return &app, nil
+}
+
+// GetHealthyInstances returns the cached UP instances of the application,
+// the ones in InitOptions.SelfZone first when InitOptions.PreferSameZone is
+// set.
+func (c *RegistryCache) GetHealthyInstances(ctx context.Context, appName string) ([]InstanceDetails, error) {
+	app, err := c.GetApplication(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+	instances := filterByStatus(app.Instance, StatusUp)
+	if c.r.preferSameZone() {
+		c.r.sortBySameZone(instances)
+	}
+	return instances, nil
+}
+
+func (c *RegistryCache) refreshIfStale(ctx context.Context) error {
+	c.mu.RLock()
+	freshFor := c.ttl
+	if c.maxAge > freshFor {
+		freshFor = c.maxAge
+	}
+	stale := time.Since(c.fetched) > c.r.pollInterval(freshFor)
+	empty := c.apps == nil
+	c.mu.RUnlock()
+	if !stale {
+		return nil
+	}
+
+	err := c.Refresh(ctx)
+	if err != nil && !empty {
+		c.r.logger.Println("Can't refresh registry cache. Using stale registry.", err)
+		return nil
+	}
+	return err
+}
+
+func filterByStatus(instances []InstanceDetails, status InstanceStatus) []InstanceDetails {
+	filtered := make([]InstanceDetails, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Status == string(status) {
+			filtered = append(filtered, instance)
+		}
+	}
+	return filtered
+}
+
+// resolveConflicts returns fetched, except for the instances that cached
+// holds a strictly more recent version of according to lastDirtyTimestamp.
+// This guards against a lagging Eureka peer serving outdated instance data.
+func resolveConflicts(cached, fetched Application) Application {
+	if len(cached.Instance) == 0 {
+		return fetched
+	}
+	known := make(map[string]InstanceDetails, len(cached.Instance))
+	for _, instance := range cached.Instance {
+		known[instance.InstanceId] = instance
+	}
+
+	resolved := make([]InstanceDetails, len(fetched.Instance))
+	for i, instance := range fetched.Instance {
+		resolved[i] = instance
+		if previous, ok := known[instance.InstanceId]; ok && previous.LastDirtyTimestamp > instance.LastDirtyTimestamp {
+			resolved[i] = previous
+		}
+	}
+	fetched.Instance = resolved
+	return fetched
+}
+
+// parseCacheControl extracts max-age and whether no-store is set from a
+// Cache-Control header value.
+func parseCacheControl(value string) (maxAge time.Duration, noStore bool) {
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store":
+			noStore = true
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil && seconds > 0 {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return maxAge, noStore
+}
@@ -0,0 +1,128 @@
+package eureka_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"github.com/abetobing/go-eureka/server"
+)
+
+// etagServer tags the registry served by the fake Eureka server with an
+// ETag of its version, answering 304 Not Modified to a fetch with a
+// matching If-None-Match, and records the If-None-Match of every fetch.
+type etagServer struct {
+	next http.Handler
+
+	mu          sync.Mutex
+	version     int
+	ifNoneMatch []string
+	notModified int
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet || req.URL.Path != "/apps" {
+		s.next.ServeHTTP(w, req)
+		return
+	}
+	s.mu.Lock()
+	etag := strconv.Quote("v" + strconv.Itoa(s.version))
+	s.ifNoneMatch = append(s.ifNoneMatch, req.Header.Get("If-None-Match"))
+	match := req.Header.Get("If-None-Match") == etag
+	if match {
+		s.notModified++
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("ETag", etag)
+	if match {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.next.ServeHTTP(w, req)
+}
+
+func (s *etagServer) bump() {
+	s.mu.Lock()
+	s.version++
+	s.mu.Unlock()
+}
+
+func TestRegistryCacheETagRefresh(t *testing.T) {
+	srv := &etagServer{next: server.NewEurekaServer()}
+	ts := newCountingServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	orders, err := eureka.NewEureka(ts.zone(), "ORDERS", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := orders.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer orders.Stop(ctx)
+	payments, err := eureka.NewEureka(ts.zone(), "PAYMENTS", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := eureka.NewRegistryCache(orders, 0)
+
+	tests := []struct {
+		name            string
+		change          func()
+		wantIfNoneMatch string
+		wantNotModified int
+		wantPayments    bool
+	}{
+		{name: "first fetch", wantIfNoneMatch: ""},
+		{name: "unchanged registry", wantIfNoneMatch: `"v0"`, wantNotModified: 1},
+		{
+			name: "changed registry",
+			change: func() {
+				if err := payments.Start(ctx); err != nil {
+					t.Fatal(err)
+				}
+				srv.bump()
+			},
+			wantIfNoneMatch: `"v0"`,
+			wantNotModified: 1,
+			wantPayments:    true,
+		},
+		{name: "unchanged again", wantIfNoneMatch: `"v1"`, wantNotModified: 2, wantPayments: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.change != nil {
+				tt.change()
+			}
+			if err := cache.Refresh(ctx); err != nil {
+				t.Fatalf("Refresh() error = %v", err)
+			}
+
+			srv.mu.Lock()
+			ifNoneMatch := srv.ifNoneMatch[len(srv.ifNoneMatch)-1]
+			notModified := srv.notModified
+			srv.mu.Unlock()
+			if ifNoneMatch != tt.wantIfNoneMatch {
+				t.Errorf("If-None-Match = %q, want %q", ifNoneMatch, tt.wantIfNoneMatch)
+			}
+			if notModified != tt.wantNotModified {
+				t.Errorf("%d not modified responses, want %d", notModified, tt.wantNotModified)
+			}
+
+			// The cached copy survives 304 Not Modified.
+			if _, err := cache.GetApplication(ctx, "ORDERS"); err != nil {
+				t.Errorf("ORDERS not cached: %v", err)
+			}
+			_, err := cache.GetApplication(ctx, "PAYMENTS")
+			if gotPayments := err == nil; gotPayments != tt.wantPayments {
+				t.Errorf("PAYMENTS cached = %v, want %v", gotPayments, tt.wantPayments)
+			}
+		})
+	}
+	payments.Stop(ctx)
+}
@@ -0,0 +1,46 @@
+package eureka
+
+import (
+	"context"
+	"time"
+)
+
+// CheckIn tells a registry with InitOptions.HeartbeatGated set that the
+// application is alive, keeping heartbeats flowing for two more
+// HeartbeatIntervals, the way a TTL health check works in Consul. When
+// heartbeats had stopped for lack of check-ins, one is sent right away.
+func (r *Registry) CheckIn(ctx context.Context) error {
+	r.state.mu.Lock()
+	resumed := r.opt.HeartbeatGated && !r.state.lastCheckIn.IsZero() &&
+		time.Since(r.state.lastCheckIn) > r.checkInTTL()
+	r.state.lastCheckIn = time.Now()
+	r.state.mu.Unlock()
+
+	if !resumed {
+		return nil
+	}
+	err := r.renew(ctx)
+	r.recordHeartbeat(err)
+	return err
+}
+
+// heartbeatAllowed tells whether the heartbeat daemon may send the next
+// heartbeat.
+func (r *Registry) heartbeatAllowed() bool {
+	if !r.opt.HeartbeatGated {
+		return true
+	}
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	if time.Since(r.state.lastCheckIn) <= r.checkInTTL() {
+		return true
+	}
+	if r.opt.Verbose {
+		r.logger.Println("No check-in from the application, skipping heartbeat to Eureka")
+	}
+	return false
+}
+
+func (r *Registry) checkInTTL() time.Duration {
+	return 2 * r.heartbeatInterval()
+}
@@ -0,0 +1,57 @@
+package eureka
+
+// Clone returns a copy of r with a new instance id and none of the
+// registration state of r, e.g. to register the same app to the Eureka
+// server of a staging mirror by changing DefaultZone before calling Start.
+// The copy has its own HTTP client and rate limiter, and its own copy of the
+// options: the metadata, lists, TLS configs, root CAs, client certificate
+// and PreferIPAddress can be changed without affecting r. The RetryPolicy,
+// DataCenterProvider and Logger are shared.
+func (r *Registry) Clone() (*Registry, error) {
+	opt := *r.opt
+	if opt.Metadata != nil {
+		opt.Metadata = make(map[string]string, len(r.opt.Metadata))
+		for k, v := range r.opt.Metadata {
+			opt.Metadata[k] = v
+		}
+	}
+	opt.DefaultZones = append([]string(nil), r.opt.DefaultZones...)
+	opt.Dependencies = append([]string(nil), r.opt.Dependencies...)
+	opt.NonRetryableStatusCodes = append([]int(nil), r.opt.NonRetryableStatusCodes...)
+	if r.opt.PreferIPAddress != nil {
+		preferIPAddress := *r.opt.PreferIPAddress
+		opt.PreferIPAddress = &preferIPAddress
+	}
+	if r.opt.TLSConfig != nil {
+		opt.TLSConfig = r.opt.TLSConfig.Clone()
+	}
+	if r.opt.ServingTLSConfig != nil {
+		opt.ServingTLSConfig = r.opt.ServingTLSConfig.Clone()
+	}
+	if r.opt.RootCAs != nil {
+		opt.RootCAs = r.opt.RootCAs.Clone()
+	}
+	if r.opt.ClientCertificate != nil {
+		cert := *r.opt.ClientCertificate
+		opt.ClientCertificate = &cert
+	}
+
+	r2 := *r
+	r2.opt = &opt
+	r2.state = &registryState{
+		metadata: r.metadataSnapshot(),
+		metrics:  r.state.metrics,
+	}
+	client, err := r2.newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	r2.client = client
+	r2.limiter = r2.newRateLimiter()
+	instanceId, err := r2.newInstanceId()
+	if err != nil {
+		return nil, err
+	}
+	r2.InstanceId = instanceId
+	return &r2, nil
+}
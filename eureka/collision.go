@@ -0,0 +1,41 @@
+package eureka
+
+import (
+	"context"
+)
+
+// checkInstanceIdCollision checks, right after a successful registration,
+// whether more than one instance is registered under r.InstanceId. On a
+// collision it either regenerates the instance id and reports that the
+// caller should register again, or returns ErrInstanceIdCollision, depending
+// on InitOptions.ResolveIDCollisions. A failure to fetch the application is
+// logged and otherwise ignored.
+func (r *Registry) checkInstanceIdCollision(ctx context.Context) (bool, error) {
+	app, err := r.GetApplication(ctx, r.AppName)
+	if err != nil {
+		r.logger.Printf("Cannot check for instance id collisions. %v\n", err)
+		return false, nil
+	}
+
+	count := 0
+	for _, instance := range app.Instance {
+		if instance.InstanceId == r.InstanceId {
+			count++
+		}
+	}
+	if count <= 1 {
+		return false, nil
+	}
+
+	r.logger.Printf("%d instances are registered to Eureka with instance id %s\n", count, r.InstanceId)
+	if !r.opt.ResolveIDCollisions {
+		return false, ErrInstanceIdCollision
+	}
+	instanceId, err := r.newInstanceId()
+	if err != nil {
+		return false, err
+	}
+	r.logger.Printf("Registering again with instance id %s\n", instanceId)
+	r.InstanceId = instanceId
+	return true, nil
+}
@@ -0,0 +1,35 @@
+package eureka
+
+import (
+	"context"
+	"time"
+)
+
+const conflictRecoveryDelay = time.Second
+
+// recoverFromConflict handles a 409 Conflict on registration, which Eureka
+// answers when a previous incarnation of the instance restarted before its
+// lease expired. It deregisters the stale instance and waits a second before
+// the caller registers again. Recovery is attempted once until the next
+// successful registration; it returns false when it was already attempted.
+func (r *Registry) recoverFromConflict(ctx context.Context) bool {
+	r.state.mu.Lock()
+	recovered := r.state.conflictRecovered
+	r.state.conflictRecovered = true
+	r.state.mu.Unlock()
+	if recovered {
+		return false
+	}
+
+	r.logger.Println("Registration conflicts with a stale instance. Deregistering it before registering again")
+	if err := r.Deregister(ctx); err != nil {
+		r.logger.Printf("Error deregistering stale instance. %v\n", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(conflictRecoveryDelay):
+		return true
+	}
+}
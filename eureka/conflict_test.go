@@ -0,0 +1,68 @@
+package eureka_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"github.com/abetobing/go-eureka/server"
+)
+
+// conflictingServer answers the first registration with 409 Conflict, the
+// way Eureka does while the lease of a previous incarnation is alive, and
+// hands every other request to the fake Eureka server.
+type conflictingServer struct {
+	next http.Handler
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func (s *conflictingServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req.Method+" "+req.URL.Path)
+	first := len(s.requests) == 1
+	s.mu.Unlock()
+
+	if first && req.Method == http.MethodPost {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	s.next.ServeHTTP(w, req)
+}
+
+func TestStartRecoversFromConflict(t *testing.T) {
+	srv := &conflictingServer{next: server.NewEurekaServer()}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	r, err := eureka.NewEureka(ts.URL, "APP", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := r.Start(ctx); err != nil {
+		t.Fatalf("Start after a conflict failed: %v", err)
+	}
+	defer r.Stop(ctx)
+
+	srv.mu.Lock()
+	requests := append([]string(nil), srv.requests...)
+	srv.mu.Unlock()
+	registerPath := "POST /apps/APP"
+	deregisterPath := "DELETE /apps/APP/" + r.InstanceId
+	if len(requests) < 3 || requests[0] != registerPath || requests[1] != deregisterPath || requests[2] != registerPath {
+		t.Fatalf("requests = %v, want %q, then %q, then %q", requests, registerPath, deregisterPath, registerPath)
+	}
+
+	instance, err := r.GetInstanceByID(ctx, "APP", r.InstanceId)
+	if err != nil {
+		t.Fatalf("instance not registered after recovering: %v", err)
+	}
+	if instance.Status != string(eureka.StatusUp) {
+		t.Errorf("status = %s, want UP", instance.Status)
+	}
+}
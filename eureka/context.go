@@ -0,0 +1,70 @@
+package eureka
+
+import "context"
+
+// WithContext returns a shallow copy of r whose operations all use ctx as
+// parent context, the way trace IDs or deadlines can be attached to every
+// Eureka call made on behalf of a request without threading a context
+// through each of them. The copy shares its state, registration and
+// heartbeats with r.
+func (r *Registry) WithContext(ctx context.Context) *Registry {
+	if ctx == nil {
+		panic("nil context")
+	}
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
+}
+
+// baseContext returns the context given to WithContext, if any.
+func (r *Registry) baseContext() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
+}
+
+// mergeContext returns a context that carries the values of ctx and of the
+// base context, and is done as soon as either of them is. The returned
+// cancel function must be called once the request is over.
+func (r *Registry) mergeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	base := r.ctx
+	if base == nil || base == ctx {
+		return ctx, func() {}
+	}
+
+	merged, cancel := context.WithCancel(ctx)
+	if deadline, ok := base.Deadline(); ok {
+		var cancelDeadline context.CancelFunc
+		merged, cancelDeadline = context.WithDeadline(merged, deadline)
+		cancelParent := cancel
+		cancel = func() {
+			cancelDeadline()
+			cancelParent()
+		}
+	}
+	if base.Done() != nil {
+		go func() {
+			select {
+			case <-base.Done():
+				cancel()
+			case <-merged.Done():
+			}
+		}()
+	}
+	return valuesContext{Context: merged, base: base}, cancel
+}
+
+// valuesContext looks values up in the base context when the embedded
+// context does not have them.
+type valuesContext struct {
+	context.Context
+	base context.Context
+}
+
+func (c valuesContext) Value(key interface{}) interface{} {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.base.Value(key)
+}
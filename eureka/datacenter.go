@@ -0,0 +1,86 @@
+package eureka
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	defaultDataCenterInfoClass = "com.netflix.appinfo.InstanceInfo$DefaultDataCenterInfo"
+	amazonDataCenterInfoClass  = "com.netflix.appinfo.AmazonInfo"
+)
+
+// AmazonMetadata is the EC2 metadata Eureka expects in the data center info
+// of instances running on Amazon.
+type AmazonMetadata struct {
+	AmiId            string `json:"ami-id,omitempty"`
+	InstanceId       string `json:"instance-id,omitempty"`
+	AvailabilityZone string `json:"availability-zone,omitempty"`
+	PublicHostname   string `json:"public-hostname,omitempty"`
+	PublicIpv4       string `json:"public-ipv4,omitempty"`
+	LocalHostname    string `json:"local-hostname,omitempty"`
+	LocalIpv4        string `json:"local-ipv4,omitempty"`
+}
+
+// NewMyOwnDataCenterInfo returns the data center info for instances running
+// in a private data center. This is what registrations use by default.
+func NewMyOwnDataCenterInfo() DataCenterInfo {
+	return DataCenterInfo{Class: defaultDataCenterInfoClass, Name: "MyOwn"}
+}
+
+// NewAmazonDataCenterInfo returns the data center info for instances running
+// on EC2, carrying the given instance metadata.
+func NewAmazonDataCenterInfo(metadata AmazonMetadata) DataCenterInfo {
+	return DataCenterInfo{Class: amazonDataCenterInfoClass, Name: "Amazon", Metadata: &metadata}
+}
+
+// NewNetflixDataCenterInfo returns the data center info for instances running
+// in a Netflix data center.
+func NewNetflixDataCenterInfo() DataCenterInfo {
+	return DataCenterInfo{Class: defaultDataCenterInfoClass, Name: "Netflix"}
+}
+
+// DataCenterProvider looks up the data center the instance runs in, along
+// with metadata describing the instance in that data center.
+type DataCenterProvider func(ctx context.Context) (DataCenterInfo, map[string]string, error)
+
+// WithDataCenterProvider makes the registry ask provider for its data center
+// info at registration time instead of assuming MyOwn. When the provider
+// fails the registration still goes through with the MyOwn data center.
+func WithDataCenterProvider(provider DataCenterProvider) Option {
+	return func(r *Registry) error {
+		r.opt.DataCenterProvider = provider
+		return nil
+	}
+}
+
+// dataCenterInfo returns the data center info and provider metadata to
+// register with. A successful lookup is remembered for later registrations.
+func (r *Registry) dataCenterInfo() (DataCenterInfo, map[string]string) {
+	if r.opt.DataCenterProvider == nil {
+		return NewMyOwnDataCenterInfo(), nil
+	}
+
+	r.state.mu.Lock()
+	cached := r.state.dataCenter
+	r.state.mu.Unlock()
+	if cached != nil {
+		return cached.info, cached.metadata
+	}
+
+	info, metadata, err := r.opt.DataCenterProvider(r.baseContext())
+	if err != nil {
+		r.logger.Println(fmt.Errorf("Can't get data center info. Using MyOwn as default. %v", err))
+		return NewMyOwnDataCenterInfo(), nil
+	}
+
+	r.state.mu.Lock()
+	r.state.dataCenter = &providedDataCenter{info: info, metadata: metadata}
+	r.state.mu.Unlock()
+	return info, metadata
+}
+
+type providedDataCenter struct {
+	info     DataCenterInfo
+	metadata map[string]string
+}
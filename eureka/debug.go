@@ -0,0 +1,55 @@
+package eureka
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"time"
+)
+
+// WithHTTPDebugLog dumps every request sent to Eureka and every response
+// received, bodies included, to w. Credentials are dumped as well, so this
+// is meant for troubleshooting only and never for production.
+func WithHTTPDebugLog(w io.Writer) Option {
+	return func(r *Registry) error {
+		r.httpDebugLog = w
+		return nil
+	}
+}
+
+// debugTransport writes the wire format of each round trip to w.
+type debugTransport struct {
+	next http.RoundTripper
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if dump, err := httputil.DumpRequestOut(req, true); err != nil {
+		t.write("Cannot dump request. %v\n", err)
+	} else {
+		t.write("---> %s\n%s\n\n", time.Now().Format(time.RFC3339), dump)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.write("<--- %s\n%v\n\n", time.Now().Format(time.RFC3339), err)
+		return nil, err
+	}
+
+	if dump, err := httputil.DumpResponse(resp, true); err != nil {
+		t.write("Cannot dump response. %v\n", err)
+	} else {
+		t.write("<--- %s\n%s\n\n", time.Now().Format(time.RFC3339), dump)
+	}
+	return resp, nil
+}
+
+func (t *debugTransport) write(format string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.w, format, args...)
+}
@@ -0,0 +1,39 @@
+package eureka
+
+import (
+	"context"
+	"strings"
+)
+
+// DependenciesMetadataKey is the metadata key an instance declares the
+// applications it depends on under, as a comma-separated list of app names.
+const DependenciesMetadataKey = "eureka.dependencies"
+
+// WithDependencies declares the applications the instance depends on.
+func WithDependencies(appNames ...string) Option {
+	return func(r *Registry) error {
+		r.opt.Dependencies = append([]string(nil), appNames...)
+		return nil
+	}
+}
+
+// GetDependencies returns the applications declared as dependencies by the
+// first UP instance of appName.
+func (r *Registry) GetDependencies(ctx context.Context, appName string) ([]string, error) {
+	app, err := r.GetApplication(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+	instances := filterByStatus(app.Instance, StatusUp)
+	if len(instances) == 0 {
+		return nil, ErrNoInstances
+	}
+
+	var dependencies []string
+	for _, name := range strings.Split(r.instanceMetadata(&instances[0])[DependenciesMetadataKey], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			dependencies = append(dependencies, name)
+		}
+	}
+	return dependencies, nil
+}
@@ -0,0 +1,72 @@
+package eureka
+
+import (
+	"context"
+	"net"
+	"strings"
+	"unicode"
+)
+
+// Dial connects to an instance of appName, picked by a balancer over its UP
+// instances. When appName is empty it is taken from the host of addr if that
+// looks like a Eureka app name (all uppercase, no dots); any other addr is
+// dialed as is. The port of addr is ignored for instances, whose registered
+// port is used instead, except that port 443 selects their secure port.
+func (r *Registry) Dial(ctx context.Context, appName, addr, network string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if appName == "" {
+		if !looksLikeAppName(host) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		}
+		appName = host
+	}
+
+	instance, err := r.dialBalancer(appName).Pick(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	instancePort := instance.Port.Port
+	if port == "443" && instance.SecurePort.Enabled == "true" {
+		instancePort = instance.SecurePort.Port
+	}
+
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, net.JoinHostPort(instance.IpAddr, instancePort))
+}
+
+// DialContext is Dial with the signature of http.Transport.DialContext, so
+// that existing HTTP clients can reach http://MY_APP/ through Eureka without
+// changing their URLs.
+func (r *Registry) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return r.Dial(ctx, "", addr, network)
+}
+
+// dialBalancer returns the balancer Dial uses for appName. All of them share
+// a single registry cache.
+func (r *Registry) dialBalancer(appName string) *Balancer {
+	appName = r.normalizeAppName(appName)
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	if r.state.dialCache == nil {
+		r.state.dialCache = NewRegistryCache(r, 0)
+		r.state.dialBalancers = make(map[string]*Balancer)
+	}
+	b, ok := r.state.dialBalancers[appName]
+	if !ok {
+		b = NewBalancer(r, appName, BalancerOptions{Cache: r.state.dialCache})
+		r.state.dialBalancers[appName] = b
+	}
+	return b
+}
+
+func looksLikeAppName(host string) bool {
+	if host == "" || strings.Contains(host, ".") || net.ParseIP(host) != nil {
+		return false
+	}
+	return host == strings.ToUpper(host) && strings.IndexFunc(host, unicode.IsLetter) >= 0
+}
@@ -0,0 +1,347 @@
+package eureka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// batchFetchThreshold is the number of applications above which
+// GetApplicationsBatch fetches the full registry instead of each application.
+const batchFetchThreshold = 10
+
+const defaultMaxConcurrentRequests = 10
+
+// GetApplication fetches the application with the given name and all its
+// instances. The name is uppercased like the app name of the registry.
+func (r *Registry) GetApplication(ctx context.Context, appName string) (*Application, error) {
+	url := fmt.Sprintf("%s/apps/%s", r.CurrentZone(), r.normalizeAppName(appName))
+
+	resp, err := r.getRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newEurekaError("Fetching application", resp)
+	}
+
+	var body ApplicationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("Cannot unmarshal application body. %v", err)
+	}
+	return &body.Application, nil
+}
+
+// GetApplicationsBatch fetches several applications at once, keyed by their
+// uppercased name. Up to batchFetchThreshold applications are fetched
+// concurrently, at most InitOptions.MaxConcurrentRequests at a time, the
+// first failure cancelling the other requests; beyond that
+// the full registry is fetched once and filtered. It fails with
+// ErrApplicationNotFound if an application is not registered.
+func (r *Registry) GetApplicationsBatch(ctx context.Context, appNames ...string) (map[string]*Application, error) {
+	result := make(map[string]*Application, len(appNames))
+	if len(appNames) > batchFetchThreshold {
+		apps, err := r.GetApplications(ctx)
+		if err != nil {
+			return nil, err
+		}
+		registered := make(map[string]*Application, len(apps.Application))
+		for i := range apps.Application {
+			registered[r.normalizeAppName(apps.Application[i].Name)] = &apps.Application[i]
+		}
+		for _, appName := range appNames {
+			name := r.normalizeAppName(appName)
+			app, ok := registered[name]
+			if !ok {
+				return nil, ErrApplicationNotFound
+			}
+			result[name] = app
+		}
+		return result, nil
+	}
+
+	var mu sync.Mutex
+	sem := make(chan struct{}, r.maxConcurrentRequests())
+	g, ctx := errgroup.WithContext(ctx)
+	for _, appName := range appNames {
+		name := r.normalizeAppName(appName)
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+
+			app, err := r.GetApplication(ctx, name)
+			var eurekaErr *EurekaError
+			if errors.As(err, &eurekaErr) && eurekaErr.StatusCode == 404 {
+				return ErrApplicationNotFound
+			}
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			result[name] = app
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetStatusCounts fetches the application and counts its instances by
+// status, e.g. {UP: 5, DOWN: 1, STARTING: 2} for a health dashboard.
+func (r *Registry) GetStatusCounts(ctx context.Context, appName string) (map[InstanceStatus]int, error) {
+	app, err := r.GetApplication(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[InstanceStatus]int)
+	for _, instance := range app.Instance {
+		counts[InstanceStatus(instance.Status)]++
+	}
+	return counts, nil
+}
+
+// GetInstanceByID fetches a single instance of the given application.
+func (r *Registry) GetInstanceByID(ctx context.Context, appName, instanceId string) (*InstanceDetails, error) {
+	url := fmt.Sprintf("%s/apps/%s/%s", r.CurrentZone(), r.normalizeAppName(appName), instanceId)
+
+	resp, err := r.getRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newEurekaError("Fetching instance", resp)
+	}
+
+	var body InstanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("Cannot unmarshal instance body. %v", err)
+	}
+	return &body.Instance, nil
+}
+
+// GetInstanceGlobal fetches a single instance by its id alone, without
+// knowing which application it belongs to.
+func (r *Registry) GetInstanceGlobal(ctx context.Context, instanceId string) (*InstanceDetails, error) {
+	url := fmt.Sprintf("%s/instances/%s", r.CurrentZone(), instanceId)
+
+	resp, err := r.getRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newEurekaError("Fetching instance", resp)
+	}
+
+	var body InstanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("Cannot unmarshal instance body. %v", err)
+	}
+	return &body.Instance, nil
+}
+
+// GetApplications fetches the full registry.
+func (r *Registry) GetApplications(ctx context.Context) (*Applications, error) {
+	apps, _, err := r.getApplications(ctx, "")
+	return apps, err
+}
+
+// GetApplicationNames fetches the names of all registered applications. The
+// instances in the response are only decoded down to their ids rather than
+// unmarshalled, which matters for large registries.
+func (r *Registry) GetApplicationNames(ctx context.Context) ([]string, error) {
+	apps, err := r.getAppSummaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(apps))
+	for _, app := range apps {
+		names = append(names, app.Name)
+	}
+	return names, nil
+}
+
+// GetAllInstanceIDs fetches the ids of all registered instances, grouped by
+// app name, e.g. to spot duplicate registrations. Like GetApplicationNames,
+// it only decodes the names and ids out of the response.
+func (r *Registry) GetAllInstanceIDs(ctx context.Context) (map[string][]string, error) {
+	apps, err := r.getAppSummaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string][]string, len(apps))
+	for _, app := range apps {
+		appIds := make([]string, 0, len(app.Instance))
+		for _, instance := range app.Instance {
+			appIds = append(appIds, instance.InstanceId)
+		}
+		ids[app.Name] = appIds
+	}
+	return ids, nil
+}
+
+// appSummary is a registered application decoded down to its name and the
+// ids of its instances.
+type appSummary struct {
+	Name     string `json:"name"`
+	Instance []struct {
+		InstanceId string `json:"instanceId"`
+	} `json:"instance"`
+}
+
+// getAppSummaries fetches the full registry like getApplications, 429 Too
+// Many Requests included, decoding only the summary of each application.
+func (r *Registry) getAppSummaries(ctx context.Context) ([]appSummary, error) {
+	var apps []appSummary
+	err := r.retryThrottled(ctx, func() (err error) {
+		apps, err = r.fetchAppSummaries(ctx)
+		return err
+	})
+	return apps, err
+}
+
+func (r *Registry) fetchAppSummaries(ctx context.Context) ([]appSummary, error) {
+	url := fmt.Sprintf("%s/apps", r.CurrentZone())
+
+	resp, err := r.getRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newEurekaError("Fetching applications", resp)
+	}
+
+	var body struct {
+		Applications struct {
+			Application []appSummary `json:"application"`
+		} `json:"applications"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("Cannot unmarshal applications body. %v", err)
+	}
+	return body.Applications.Application, nil
+}
+
+// GetAllInstancesByStatus fetches the full registry and returns the
+// instances having the given status, grouped by app name. Applications
+// without such instance are left out.
+func (r *Registry) GetAllInstancesByStatus(ctx context.Context, status InstanceStatus) (map[string][]InstanceDetails, error) {
+	apps, err := r.GetApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]InstanceDetails)
+	for _, app := range apps.Application {
+		if instances := filterByStatus(app.Instance, status); len(instances) > 0 {
+			result[app.Name] = instances
+		}
+	}
+	return result, nil
+}
+
+// getApplications fetches the full registry unless its ETag still matches
+// etag, in which case it returns nil applications. The response header is
+// returned for its caching directives. A 429 Too Many Requests is retried
+// as retryThrottled does.
+func (r *Registry) getApplications(ctx context.Context, etag string) (*Applications, http.Header, error) {
+	var apps *Applications
+	var header http.Header
+	err := r.retryThrottled(ctx, func() (err error) {
+		apps, header, err = r.fetchApplications(ctx, etag)
+		return err
+	})
+	return apps, header, err
+}
+
+// retryThrottled calls fetch, a registry fetch, again after the delay given
+// by throttled for as long as it fails with 429 Too Many Requests, until
+// throttleSlowdownThreshold of them in a row fail it with the EurekaError;
+// from then on the polling callers slow down.
+func (r *Registry) retryThrottled(ctx context.Context, fetch func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fetch()
+		var eurekaErr *EurekaError
+		if !errors.As(err, &eurekaErr) || eurekaErr.StatusCode != http.StatusTooManyRequests {
+			if err == nil {
+				r.resetThrottle()
+			}
+			return err
+		}
+		delay := r.throttled(eurekaErr.resp)
+		if attempt >= throttleSlowdownThreshold {
+			return err
+		}
+		r.logger.Printf("Eureka is throttling registry fetches, retrying in %v\n", delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (r *Registry) fetchApplications(ctx context.Context, etag string) (*Applications, http.Header, error) {
+	url := fmt.Sprintf("%s/apps", r.CurrentZone())
+
+	resp, err := r.conditionalGetRequest(ctx, url, etag)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return nil, resp.Header, nil
+	}
+	if resp.StatusCode != 200 {
+		return nil, nil, newEurekaError("Fetching applications", resp)
+	}
+
+	var body ApplicationsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, nil, fmt.Errorf("Cannot unmarshal applications body. %v", err)
+	}
+	return &body.Applications, resp.Header, nil
+}
+
+// GetServerInfo fetches the info the Eureka server publishes about itself,
+// such as its version, uptime and peers. The layout of the info depends on
+// the server, so it is returned as decoded JSON.
+func (r *Registry) GetServerInfo(ctx context.Context) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/info", r.CurrentZone())
+
+	resp, err := r.getRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newEurekaError("Fetching server info", resp)
+	}
+
+	var info map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("Cannot unmarshal server info body. %v", err)
+	}
+	return info, nil
+}
@@ -0,0 +1,100 @@
+package eureka
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// DiscoveryClient is the high-level way to both register an application to
+// Eureka and look up others, the place to start for most services. The
+// embedded Registry remains available for everything else.
+type DiscoveryClient struct {
+	*Registry
+
+	// WatchInterval is how often Watch polls. Defaults to 30 seconds.
+	WatchInterval time.Duration
+}
+
+// NewDiscoveryClient returns a DiscoveryClient registering appName to the
+// Eureka server at eurekaServerUrl, configured by opts.
+func NewDiscoveryClient(eurekaServerUrl, appName string, opts ...Option) (*DiscoveryClient, error) {
+	r, err := NewEureka(eurekaServerUrl, appName, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &DiscoveryClient{Registry: r}, nil
+}
+
+// Register registers the instance, marks it UP and keeps sending heartbeats
+// until Deregister is called. See Registry.Start.
+func (c *DiscoveryClient) Register(ctx context.Context) error {
+	return c.Start(ctx)
+}
+
+// Deregister stops sending heartbeats and removes the instance from Eureka.
+func (c *DiscoveryClient) Deregister(ctx context.Context) error {
+	return c.Stop(ctx)
+}
+
+// Lookup returns the UP instances of an application.
+func (c *DiscoveryClient) Lookup(ctx context.Context, appName string) ([]InstanceDetails, error) {
+	app, err := c.GetApplication(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+	return filterByStatus(app.Instance, StatusUp), nil
+}
+
+// Self returns the instance as it registers itself to Eureka.
+func (c *DiscoveryClient) Self() *InstanceDetails {
+	status := c.Status()
+	if status == "" {
+		status = StatusStarting
+	}
+	return &c.buildBody(string(status)).Instance
+}
+
+// Watch polls the application every WatchInterval and calls handler with
+// its UP instances, sorted by instance id, on the first poll and whenever
+// they change. An application that is not registered counts as having no
+// instances; other failures are logged and the next poll tries again. It
+// blocks until ctx is done, then returns ctx.Err().
+func (c *DiscoveryClient) Watch(ctx context.Context, appName string, handler func(instances []InstanceDetails)) error {
+	interval := c.WatchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	var known map[string]InstanceDetails
+	for {
+		all, err := c.applicationInstances(ctx, appName)
+		if err != nil {
+			c.logger.Printf("Cannot watch application %s. %v\n", appName, err)
+		} else {
+			current := make(map[string]InstanceDetails)
+			for id, instance := range all {
+				if instance.Status == string(StatusUp) {
+					current[id] = instance
+				}
+			}
+			if known == nil || len(diffInstances(known, current)) > 0 {
+				instances := make([]InstanceDetails, 0, len(current))
+				for _, instance := range current {
+					instances = append(instances, instance)
+				}
+				sort.Slice(instances, func(i, j int) bool {
+					return instances[i].InstanceId < instances[j].InstanceId
+				})
+				handler(instances)
+			}
+			known = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.pollInterval(interval)):
+		}
+	}
+}
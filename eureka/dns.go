@@ -0,0 +1,83 @@
+package eureka
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+const defaultDNSCacheTTL = 30 * time.Second
+
+// dnsCacheDialer resolves host names once per TTL instead of on every new
+// connection, sparing each heartbeat a DNS round trip.
+type dnsCacheDialer struct {
+	dialer   *net.Dialer
+	resolver *net.Resolver
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+func newDNSCacheDialer(dialer *net.Dialer, ttl time.Duration) *dnsCacheDialer {
+	return &dnsCacheDialer{
+		dialer:   dialer,
+		resolver: net.DefaultResolver,
+		ttl:      ttl,
+		entries:  make(map[string]dnsCacheEntry),
+	}
+}
+
+func (d *dnsCacheDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || net.ParseIP(host) != nil {
+		return d.dialer.DialContext(ctx, network, address)
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+	for _, addr := range addrs {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	// None of the cached addresses worked, resolve again next time.
+	d.mu.Lock()
+	delete(d.entries, host)
+	d.mu.Unlock()
+	return nil, firstErr
+}
+
+func (d *dnsCacheDialer) lookup(ctx context.Context, host string) ([]string, error) {
+	d.mu.Lock()
+	entry, ok := d.entries[host]
+	d.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := d.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.entries[host] = dnsCacheEntry{addrs: addrs, expires: time.Now().Add(d.ttl)}
+	d.mu.Unlock()
+	return addrs, nil
+}
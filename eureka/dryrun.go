@@ -0,0 +1,43 @@
+package eureka
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// dryRunResponse logs req instead of sending it and answers it the way an
+// empty Eureka server would: the full registry is empty, any other lookup is
+// not found, and every change succeeds.
+func (r *Registry) dryRunResponse(req *http.Request) *http.Response {
+	var body []byte
+	if req.Body != nil {
+		body, _ = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+	}
+	header := req.Header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", "***")
+	}
+	r.logger.Printf("Dry run, not sending %s %s %v %s\n", req.Method, req.URL, header, body)
+
+	status, payload := http.StatusNoContent, ""
+	if req.Method == http.MethodGet {
+		status = http.StatusNotFound
+		_, path, _ := r.splitZone(req.URL.String())
+		if operationName(req.Method, path) == "getApplications" {
+			status, payload = http.StatusOK, `{"applications":{"versions__delta":"1","apps__hashcode":"","application":[]}}`
+		}
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(payload)),
+		Request:    req,
+	}
+}
@@ -0,0 +1,223 @@
+package eureka
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// NewEurekaFromEnv returns a Registry configured from environment variables:
+// EUREKA_SERVER_URL and EUREKA_APP_NAME are required, every other InitOptions
+// field is read as described in ApplyEnvironmentOverrides, and
+// EUREKA_PRESERVE_APP_NAME_CASE opts out of uppercasing the app name.
+func NewEurekaFromEnv(opts ...Option) (*Registry, error) {
+	serverUrl := os.Getenv("EUREKA_SERVER_URL")
+	if serverUrl == "" {
+		return nil, errors.New("EUREKA_SERVER_URL is not set")
+	}
+	appName := os.Getenv("EUREKA_APP_NAME")
+	if appName == "" {
+		return nil, errors.New("EUREKA_APP_NAME is not set")
+	}
+
+	var env envReader
+	var preserveCase bool
+	env.bool("EUREKA_PRESERVE_APP_NAME_CASE", &preserveCase)
+	if preserveCase {
+		opts = append(opts, WithPreserveAppNameCase())
+	}
+	r, err := NewEureka(serverUrl, appName, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ApplyEnvironmentOverrides(); err != nil {
+		return nil, err
+	}
+	env.logInvalid(r.logger)
+	return r, nil
+}
+
+// ApplyEnvironmentOverrides overrides the options of the registry with the
+// non-empty environment variables among:
+//
+//	EUREKA_PORT, EUREKA_USERNAME, EUREKA_PASSWORD, EUREKA_VERBOSE,
+//	EUREKA_METADATA_NAMESPACE_PREFIX, EUREKA_SECURE_PORT,
+//	EUREKA_SECURE_PORT_ENABLED, EUREKA_SCHEME, EUREKA_HEALTH_CHECK_PATH,
+//	EUREKA_HEALTH_CHECK_PORT, EUREKA_HOME_PAGE_PATH,
+//	EUREKA_STATUS_PAGE_PATH, EUREKA_IP_ADDRESS, EUREKA_PREFER_IP_ADDRESS,
+//	EUREKA_FORWARDED_IP_HEADER, EUREKA_CLIENT_CERT_FILE,
+//	EUREKA_CLIENT_KEY_FILE, EUREKA_SERVER_CERT_FINGERPRINT, EUREKA_H2C,
+//	EUREKA_PROXY_URL, EUREKA_DEFAULT_ZONES, EUREKA_DRY_RUN,
+//	EUREKA_LOG_PREFIX, EUREKA_OUT_OF_SERVICE_TIMEOUT,
+//	EUREKA_IS_COORDINATING_DISCOVERY_SERVER, EUREKA_SANDBOX,
+//	EUREKA_INSTANCE_WEIGHT, EUREKA_COUNTRY_ID,
+//	EUREKA_RESOLVE_ID_COLLISIONS, EUREKA_ENVIRONMENT, EUREKA_ASG_NAME,
+//	EUREKA_SELF_ZONE, EUREKA_PREFER_SAME_ZONE, EUREKA_MAX_RETRIES,
+//	EUREKA_RETRY_INTERVAL, EUREKA_HEARTBEAT_INTERVAL,
+//	EUREKA_HEARTBEAT_GATED, EUREKA_LEASE_DURATION, EUREKA_DNS_CACHE_TTL,
+//	EUREKA_RESPONSE_HEADER_TIMEOUT, EUREKA_RATE_LIMIT, EUREKA_RATE_BURST
+//	and EUREKA_MAX_CONCURRENT_REQUESTS.
+//
+// Lists are comma separated. Durations are given either in
+// time.ParseDuration syntax or as a number of seconds. Invalid values are
+// logged and ignored. It fails when the HTTP client cannot be built with the
+// overridden options, e.g. because the client certificate cannot be loaded.
+// It must be called before the instance is registered.
+func (r *Registry) ApplyEnvironmentOverrides() error {
+	opt := r.opt
+	var env envReader
+	env.string("EUREKA_PORT", &opt.Port)
+	env.string("EUREKA_USERNAME", &opt.Username)
+	env.string("EUREKA_PASSWORD", &opt.Password)
+	env.bool("EUREKA_VERBOSE", &opt.Verbose)
+	env.string("EUREKA_METADATA_NAMESPACE_PREFIX", &opt.MetadataNamespacePrefix)
+	env.string("EUREKA_SECURE_PORT", &opt.SecurePort)
+	env.bool("EUREKA_SECURE_PORT_ENABLED", &opt.SecurePortEnabled)
+	env.string("EUREKA_SCHEME", &opt.Scheme)
+	env.string("EUREKA_HEALTH_CHECK_PATH", &opt.HealthCheckPath)
+	env.string("EUREKA_HEALTH_CHECK_PORT", &opt.HealthCheckPort)
+	env.string("EUREKA_HOME_PAGE_PATH", &opt.HomePagePath)
+	env.string("EUREKA_STATUS_PAGE_PATH", &opt.StatusPagePath)
+	env.string("EUREKA_IP_ADDRESS", &opt.IPAddress)
+	preferIP := r.preferIPAddress()
+	env.bool("EUREKA_PREFER_IP_ADDRESS", &preferIP)
+	if preferIP != r.preferIPAddress() {
+		opt.PreferIPAddress = &preferIP
+	}
+	env.string("EUREKA_FORWARDED_IP_HEADER", &opt.ForwardedIPHeader)
+	env.string("EUREKA_CLIENT_CERT_FILE", &opt.ClientCertFile)
+	env.string("EUREKA_CLIENT_KEY_FILE", &opt.ClientKeyFile)
+	env.string("EUREKA_SERVER_CERT_FINGERPRINT", &opt.ServerCertFingerprint)
+	env.bool("EUREKA_H2C", &opt.H2C)
+	env.string("EUREKA_PROXY_URL", &opt.ProxyURL)
+	env.list("EUREKA_DEFAULT_ZONES", &opt.DefaultZones)
+	env.bool("EUREKA_DRY_RUN", &opt.DryRun)
+	env.string("EUREKA_LOG_PREFIX", &opt.LogPrefix)
+	env.duration("EUREKA_OUT_OF_SERVICE_TIMEOUT", &opt.OutOfServiceTimeout)
+	env.bool("EUREKA_IS_COORDINATING_DISCOVERY_SERVER", &opt.IsCoordinatingDiscoveryServer)
+	env.bool("EUREKA_SANDBOX", &opt.IsSandboxApp)
+	env.float("EUREKA_INSTANCE_WEIGHT", &opt.InstanceWeight)
+	env.int("EUREKA_COUNTRY_ID", &opt.CountryId)
+	env.bool("EUREKA_RESOLVE_ID_COLLISIONS", &opt.ResolveIDCollisions)
+	env.string("EUREKA_ENVIRONMENT", &opt.Environment)
+	env.string("EUREKA_ASG_NAME", &opt.ASGName)
+	env.string("EUREKA_SELF_ZONE", &opt.SelfZone)
+	env.bool("EUREKA_PREFER_SAME_ZONE", &opt.PreferSameZone)
+	env.int("EUREKA_MAX_RETRIES", &opt.MaxRetries)
+	env.duration("EUREKA_RETRY_INTERVAL", &opt.RetryInterval)
+	env.duration("EUREKA_HEARTBEAT_INTERVAL", &opt.HeartbeatInterval)
+	env.bool("EUREKA_HEARTBEAT_GATED", &opt.HeartbeatGated)
+	env.duration("EUREKA_LEASE_DURATION", &opt.LeaseDuration)
+	env.duration("EUREKA_DNS_CACHE_TTL", &opt.DNSCacheTTL)
+	env.duration("EUREKA_RESPONSE_HEADER_TIMEOUT", &opt.ResponseHeaderTimeout)
+	var limit float64
+	if env.float("EUREKA_RATE_LIMIT", &limit) {
+		opt.RateLimit = rate.Limit(limit)
+	}
+	env.int("EUREKA_RATE_BURST", &opt.RateBurst)
+	env.int("EUREKA_MAX_CONCURRENT_REQUESTS", &opt.MaxConcurrentRequests)
+	if err := r.applyOptions(); err != nil {
+		return err
+	}
+	env.logInvalid(r.logger)
+	return nil
+}
+
+// envReader reads environment variables into options, collecting the
+// invalid values so that they are logged through the logger of the registry
+// once it is set up.
+type envReader struct {
+	invalid []error
+}
+
+func (e *envReader) logInvalid(logger Logger) {
+	for _, err := range e.invalid {
+		logger.Println(err)
+	}
+}
+
+func (e *envReader) ignore(key string, err error) {
+	e.invalid = append(e.invalid, fmt.Errorf("Ignoring invalid %s. %v", key, err))
+}
+
+func (e *envReader) string(key string, dst *string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+func (e *envReader) list(key string, dst *[]string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		e.ignore(key, err)
+		return
+	}
+	*dst = b
+}
+
+func (e *envReader) int(key string, dst *int) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		e.ignore(key, err)
+		return
+	}
+	*dst = i
+}
+
+func (e *envReader) float(key string, dst *float64) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return false
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		e.ignore(key, err)
+		return false
+	}
+	*dst = f
+	return true
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		*dst = time.Duration(secs) * time.Second
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		e.ignore(key, err)
+		return
+	}
+	*dst = d
+}
@@ -0,0 +1,33 @@
+package eureka
+
+import "context"
+
+const environmentMetadataKey = "environment"
+
+// GetApplicationByEnvironment fetches the application, keeping only the
+// instances registered with the given environment. This lets a single Eureka
+// cluster serve several environments.
+func (r *Registry) GetApplicationByEnvironment(ctx context.Context, appName, env string) (*Application, error) {
+	app, err := r.GetApplication(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]InstanceDetails, 0, len(app.Instance))
+	for _, instance := range app.Instance {
+		if r.instanceEnvironment(&instance) == env {
+			instances = append(instances, instance)
+		}
+	}
+	app.Instance = instances
+	return app, nil
+}
+
+// instanceEnvironment returns the environment of instance. Eureka servers
+// drop unknown instance fields, so the metadata key takes precedence.
+func (r *Registry) instanceEnvironment(instance *InstanceDetails) string {
+	if env, ok := r.instanceMetadata(instance)[environmentMetadataKey]; ok {
+		return env
+	}
+	return instance.Environment
+}
@@ -0,0 +1,33 @@
+package eureka
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// EurekaError is returned when Eureka answers a request with a status code
+// other than 200 or 204.
+type EurekaError struct {
+	Op         string
+	StatusCode int
+	Status     string
+
+	resp *http.Response
+}
+
+func newEurekaError(op string, resp *http.Response) *EurekaError {
+	return &EurekaError{Op: op, StatusCode: resp.StatusCode, Status: resp.Status, resp: resp}
+}
+
+func (e *EurekaError) Error() string {
+	return fmt.Sprintf("%s FAILED with status %v", e.Op, e.Status)
+}
+
+var (
+	ErrApplicationNotFound = errors.New("Application is not registered to Eureka")
+	ErrNoInstances         = errors.New("No UP instance available")
+	ErrBelowThreshold      = errors.New("Fewer UP instances available than the minimum healthy instance count")
+	ErrInstanceIdCollision = errors.New("Another instance is registered to Eureka with the same instance id")
+	ErrCertPinMismatch     = errors.New("Certificate of the Eureka server does not match ServerCertFingerprint")
+)
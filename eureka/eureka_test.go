@@ -0,0 +1,193 @@
+package eureka_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"github.com/abetobing/go-eureka/server"
+)
+
+// unavailableOnUp answers the first registration marking the instance UP
+// with 503 Service Unavailable and hands every other request to the fake
+// Eureka server, recording the status of every registration.
+type unavailableOnUp struct {
+	next http.Handler
+
+	mu       sync.Mutex
+	statuses []string
+}
+
+func (s *unavailableOnUp) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		body, _ := io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		var registration eureka.RequestBody
+		json.Unmarshal(body, &registration)
+
+		s.mu.Lock()
+		s.statuses = append(s.statuses, registration.Instance.Status)
+		failUp := registration.Instance.Status == "UP" && len(s.statuses) == 1
+		s.mu.Unlock()
+		if failUp {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}
+	s.next.ServeHTTP(w, req)
+}
+
+func TestUpFailureRegistersOnce(t *testing.T) {
+	srv := &unavailableOnUp{next: server.NewEurekaServer()}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	r, err := eureka.NewEureka(ts.URL, "APP", &eureka.InitOptions{Port: "8080", RetryInterval: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Up()
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	want := []string{"UP", "STARTING", "UP"}
+	if len(srv.statuses) != len(want) {
+		t.Fatalf("registrations = %v, want %v", srv.statuses, want)
+	}
+	for i := range want {
+		if srv.statuses[i] != want[i] {
+			t.Fatalf("registrations = %v, want %v", srv.statuses, want)
+		}
+	}
+}
+
+func TestInstanceDetailsNullableFields(t *testing.T) {
+	tests := []struct {
+		name             string
+		json             string
+		secureVipAddress *string
+		overriddenStatus *string
+		countryId        *int
+	}{
+		{
+			name: "null",
+			json: `{"instanceId":"i-1","secureVipAddress":null,"overriddenStatus":null,"countryId":null}`,
+		},
+		{
+			name: "missing",
+			json: `{"instanceId":"i-1"}`,
+		},
+		{
+			name:             "set",
+			json:             `{"instanceId":"i-1","secureVipAddress":"app","overriddenStatus":"UNKNOWN","countryId":1}`,
+			secureVipAddress: stringPtr("app"),
+			overriddenStatus: stringPtr("UNKNOWN"),
+			countryId:        intPtr(1),
+		},
+		{
+			name:             "empty",
+			json:             `{"instanceId":"i-1","secureVipAddress":"","countryId":0}`,
+			secureVipAddress: stringPtr(""),
+			countryId:        intPtr(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var instance eureka.InstanceDetails
+			if err := json.Unmarshal([]byte(tt.json), &instance); err != nil {
+				t.Fatal(err)
+			}
+			if !equalPtr(instance.SecureVipAddress, tt.secureVipAddress) {
+				t.Errorf("SecureVipAddress = %v, want %v", deref(instance.SecureVipAddress), deref(tt.secureVipAddress))
+			}
+			if !equalPtr(instance.OverriddenStatus, tt.overriddenStatus) {
+				t.Errorf("OverriddenStatus = %v, want %v", deref(instance.OverriddenStatus), deref(tt.overriddenStatus))
+			}
+			if !equalPtr(instance.CountryId, tt.countryId) {
+				t.Errorf("CountryId = %v, want %v", deref(instance.CountryId), deref(tt.countryId))
+			}
+		})
+	}
+}
+
+func TestBuildRegistrationBodyPreferIPAddress(t *testing.T) {
+	const ip = "10.0.0.7"
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip("no OS host name:", err)
+	}
+	tests := []struct {
+		name            string
+		preferIPAddress *bool
+		hostName        string
+	}{
+		{name: "default", preferIPAddress: nil, hostName: ip},
+		{name: "true", preferIPAddress: boolPtr(true), hostName: ip},
+		{name: "false", preferIPAddress: boolPtr(false), hostName: hostname},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := eureka.NewEureka("http://eureka.test/eureka", "APP", &eureka.InitOptions{
+				Port:            "8080",
+				IPAddress:       ip,
+				PreferIPAddress: tt.preferIPAddress,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := r.BuildRegistrationBody(eureka.StatusUp)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			instance := body.Instance
+			if instance.HostName != tt.hostName {
+				t.Errorf("hostName = %q, want %q", instance.HostName, tt.hostName)
+			}
+			if instance.IpAddr != ip {
+				t.Errorf("ipAddr = %q, want %q", instance.IpAddr, ip)
+			}
+			urls := map[string]string{
+				"homePageUrl":    instance.HomePageUrl,
+				"statusPageUrl":  instance.StatusPageUrl,
+				"healthCheckUrl": instance.HealthCheckUrl,
+			}
+			want := map[string]string{
+				"homePageUrl":    "http://10.0.0.7:8080/",
+				"statusPageUrl":  "http://10.0.0.7:8080/info",
+				"healthCheckUrl": "http://10.0.0.7:8080/health",
+			}
+			for name, url := range urls {
+				if url != want[name] {
+					t.Errorf("%s = %q, want %q", name, url, want[name])
+				}
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func stringPtr(s string) *string { return &s }
+
+func intPtr(n int) *int { return &n }
+
+func equalPtr[T comparable](got, want *T) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return *got == *want
+}
+
+func deref[T any](p *T) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
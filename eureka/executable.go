@@ -0,0 +1,34 @@
+package eureka
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// versionSuffix matches a version at the end of an executable name, as in
+// "orders_v1.2.3" or "orders-1.2".
+var versionSuffix = regexp.MustCompile(`[_-]v?[0-9]+(\.[0-9]+)*$`)
+
+// AppNameFromExecutable derives an app name from the name of the running
+// executable, without its extension and version suffix, uppercased: an
+// executable named "orders_v1.2.3.exe" registers as "ORDERS".
+func AppNameFromExecutable() string {
+	name := filepath.Base(os.Args[0])
+	if ext := filepath.Ext(name); ext != "" && !versionSuffix.MatchString("-"+ext[1:]) {
+		name = strings.TrimSuffix(name, ext)
+	}
+	name = versionSuffix.ReplaceAllString(name, "")
+	return strings.ToUpper(name)
+}
+
+// WithAppNameFromExecutable registers the instance under
+// AppNameFromExecutable instead of the app name given to NewEureka, e.g.
+// for a binary started as several services under different names.
+func WithAppNameFromExecutable() Option {
+	return func(r *Registry) error {
+		r.AppName = AppNameFromExecutable()
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package eureka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AddInstance registers an instance of another service to Eureka on its
+// behalf, e.g. a legacy service behind a gateway, under appName whatever
+// details.App says. No heartbeat is sent for it: either the service or the
+// caller keeps renewing its lease, see RenewLease, or Eureka evicts it once
+// the lease expires.
+func (r *Registry) AddInstance(ctx context.Context, appName string, details InstanceDetails) error {
+	appName = r.normalizeAppName(appName)
+	details.App = appName
+
+	body, err := json.Marshal(RequestBody{Instance: details})
+	if err != nil {
+		return fmt.Errorf("Cannot marshal instance body. %v", err)
+	}
+
+	url := fmt.Sprintf("%s/apps/%s", r.CurrentZone(), appName)
+	resp, err := r.postRequest(ctx, url, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 || resp.StatusCode == 200 {
+		return nil
+	}
+	return newEurekaError("Registration", resp)
+}
+
+// RenewLease sends a heartbeat for any instance, e.g. one added with
+// AddInstance, keeping its lease from expiring.
+func (r *Registry) RenewLease(ctx context.Context, appName, instanceId string) error {
+	if strings.TrimSpace(appName) == "" {
+		return fmt.Errorf("App name is empty")
+	}
+	if strings.TrimSpace(instanceId) == "" {
+		return fmt.Errorf("Instance id is empty")
+	}
+
+	url := fmt.Sprintf("%s/apps/%s/%s", r.CurrentZone(), r.normalizeAppName(appName), instanceId)
+	resp, err := r.putRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 || resp.StatusCode == 200 {
+		return nil
+	}
+	return newEurekaError("Heartbeat", resp)
+}
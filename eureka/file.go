@@ -0,0 +1,195 @@
+package eureka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// decodeYAML decodes a YAML config file. It is only available when built
+// with the yaml build tag, which pulls in gopkg.in/yaml.v3.
+var decodeYAML func(data []byte, config *map[string]interface{}) error
+
+// NewEurekaFromFile returns a Registry for appName configured from a JSON
+// file, or YAML file when built with the yaml build tag, told apart by
+// their .json, .yaml or .yml extension. The file holds the URL of the
+// Eureka server as eurekaServerUrl, and any InitOptions field of a string,
+// boolean, number, list or string map type under its name, first letter in
+// either case:
+//
+//	{
+//	  "eurekaServerUrl": "http://eureka.server:8761/eureka",
+//	  "port": "8080",
+//	  "heartbeatInterval": "10s",
+//	  "metadata": {"team": "payments"}
+//	}
+//
+// Durations are given either in time.ParseDuration syntax or as a number of
+// seconds. Unknown fields are rejected.
+func NewEurekaFromFile(path, appName string, opts ...Option) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read config file. %v", err)
+	}
+
+	var config map[string]interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &config)
+	case ".yaml", ".yml":
+		if decodeYAML == nil {
+			return nil, fmt.Errorf("Cannot read config file %s. YAML support requires building with the yaml build tag", path)
+		}
+		err = decodeYAML(data, &config)
+	default:
+		return nil, fmt.Errorf("Cannot read config file %s. Unknown extension %q", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Malformed config file %s. %v", path, err)
+	}
+
+	var serverUrl string
+	opt := defaultInitOptions()
+	for key, value := range config {
+		if strings.EqualFold(key, "eurekaServerUrl") {
+			var ok bool
+			if serverUrl, ok = value.(string); !ok {
+				return nil, fmt.Errorf("Malformed config file %s. eurekaServerUrl must be a string", path)
+			}
+			continue
+		}
+		if err := setFileOption(opt, key, value); err != nil {
+			return nil, fmt.Errorf("Malformed config file %s. %v", path, err)
+		}
+	}
+	if serverUrl == "" {
+		return nil, fmt.Errorf("Malformed config file %s. eurekaServerUrl is not set", path)
+	}
+	return NewEureka(serverUrl, appName, opt, opts...)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setFileOption sets the InitOptions field named key, ignoring case, to the
+// decoded value.
+func setFileOption(opt *InitOptions, key string, value interface{}) error {
+	field := reflect.ValueOf(opt).Elem().FieldByNameFunc(func(name string) bool {
+		return strings.EqualFold(name, key)
+	})
+	if !field.IsValid() {
+		return fmt.Errorf("Unknown field %q", key)
+	}
+	if err := setFileValue(field, value); err != nil {
+		return fmt.Errorf("Invalid %s. %v", key, err)
+	}
+	return nil
+}
+
+func setFileValue(field reflect.Value, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	if field.Type() == durationType {
+		d, err := fileDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("Must be a string")
+		}
+		field.SetString(s)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return errors.New("Must be a boolean")
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, ok := fileNumber(value)
+		if !ok || n != float64(int64(n)) {
+			return errors.New("Must be an integer")
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		n, ok := fileNumber(value)
+		if !ok {
+			return errors.New("Must be a number")
+		}
+		field.SetFloat(n)
+	case reflect.Ptr:
+		elem := reflect.New(field.Type().Elem())
+		if err := setFileValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return errors.New("Must be a list")
+		}
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setFileValue(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	case reflect.Map:
+		if field.Type().Key().Kind() != reflect.String || field.Type().Elem().Kind() != reflect.String {
+			return errors.New("Cannot be set from a config file")
+		}
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return errors.New("Must be a map")
+		}
+		m := make(map[string]string, len(entries))
+		for k, v := range entries {
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("Value of %q must be a string", k)
+			}
+			m[k] = s
+		}
+		field.Set(reflect.ValueOf(m))
+	default:
+		return errors.New("Cannot be set from a config file")
+	}
+	return nil
+}
+
+// fileNumber returns the number decoded from JSON, as a float64, or from
+// YAML, as an int or a float64.
+func fileNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// fileDuration parses a duration given in time.ParseDuration syntax or as a
+// number of seconds.
+func fileDuration(value interface{}) (time.Duration, error) {
+	if secs, ok := fileNumber(value); ok {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return 0, errors.New("Must be a duration")
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,11 @@
+//go:build yaml
+
+package eureka
+
+import "gopkg.in/yaml.v3"
+
+func init() {
+	decodeYAML = func(data []byte, config *map[string]interface{}) error {
+		return yaml.Unmarshal(data, config)
+	}
+}
@@ -0,0 +1,26 @@
+package eureka
+
+import "fmt"
+
+// String returns a compact summary of the registry, e.g.
+// Registry{app=MY_APP, instance=MY_APP:550e8400-..., zone=http://eureka:8761/eureka, status=UP, port=8080}.
+// Credentials are left out.
+func (r *Registry) String() string {
+	status := r.Status()
+	if status == "" {
+		status = StatusUnknown
+	}
+	return fmt.Sprintf("Registry{app=%s, instance=%s, zone=%s, status=%s, port=%s}",
+		r.AppName, r.InstanceId, r.CurrentZone(), status, r.Port)
+}
+
+// GoString formats the registry for %#v as Go syntax of its exported fields,
+// with the password masked.
+func (r *Registry) GoString() string {
+	password := ""
+	if r.Password != "" {
+		password = "****"
+	}
+	return fmt.Sprintf("&eureka.Registry{AppName:%q, DefaultZone:%q, Port:%q, Username:%q, Password:%q, InstanceId:%q}",
+		r.AppName, r.DefaultZone, r.Port, r.Username, password, r.InstanceId)
+}
@@ -0,0 +1,34 @@
+package eureka
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// SetInboundRequest takes the IP address to register the instance with from
+// the InitOptions.ForwardedIPHeader header of req, a request received through
+// the reverse proxy in front of the service. With a list of addresses like
+// X-Forwarded-For, the first one is used. It has no effect when
+// ForwardedIPHeader is not set or req does not carry a valid address, and
+// InitOptions.IPAddress takes precedence over it.
+func (r *Registry) SetInboundRequest(req *http.Request) {
+	if r.opt.ForwardedIPHeader == "" {
+		return
+	}
+	value := req.Header.Get(r.opt.ForwardedIPHeader)
+	ipAddr := strings.TrimSpace(strings.Split(value, ",")[0])
+	if net.ParseIP(ipAddr) == nil {
+		return
+	}
+
+	r.state.mu.Lock()
+	r.state.forwardedIP = ipAddr
+	r.state.mu.Unlock()
+}
+
+func (r *Registry) forwardedIP() string {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return r.state.forwardedIP
+}
@@ -0,0 +1,46 @@
+package eureka
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const defaultHealthPollInterval = time.Second
+
+// WaitForHealthy polls the health check URL registered for the instance
+// every interval (one second when zero) until it answers with a 2xx status
+// or ctx is done, e.g. to only call Start once the service is able to take
+// traffic.
+func (r *Registry) WaitForHealthy(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultHealthPollInterval
+	}
+	_, _, healthCheckUrl := r.pageUrls(r.externalIP())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if healthy(ctx, healthCheckUrl) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func healthy(ctx context.Context, url string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
@@ -0,0 +1,56 @@
+package eureka
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// DefaultInternalHealthPath is the conventional path to mount
+// InternalHealthHandler at.
+const DefaultInternalHealthPath = "/eureka-health"
+
+// InternalHealth is the state of the registration reported by
+// InternalHealthHandler.
+type InternalHealth struct {
+	Status                       InstanceStatus `json:"status"`
+	LastHeartbeat                *time.Time     `json:"lastHeartbeat,omitempty"`
+	LastHeartbeatAge             string         `json:"lastHeartbeatAge,omitempty"`
+	ConsecutiveHeartbeatFailures int            `json:"consecutiveHeartbeatFailures"`
+	EurekaServerUrl              string         `json:"eurekaServerUrl"`
+	InstanceId                   string         `json:"instanceId"`
+	RetryAttempts                int            `json:"retryAttempts"`
+}
+
+// InternalHealthHandler serves the state of the registration as JSON, for
+// debugging registration issues, e.g.
+//
+//	http.Handle(eureka.DefaultInternalHealthPath, r.InternalHealthHandler())
+func (r *Registry) InternalHealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(r.internalHealth()); err != nil {
+			r.logger.Printf("Cannot marshal internal health. %v\n", err)
+		}
+	})
+}
+
+func (r *Registry) internalHealth() InternalHealth {
+	zone := r.CurrentZone()
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	health := InternalHealth{
+		Status:                       r.state.status,
+		ConsecutiveHeartbeatFailures: r.state.heartbeatFailures,
+		EurekaServerUrl:              zone,
+		InstanceId:                   r.InstanceId,
+		RetryAttempts:                r.state.attempts,
+	}
+	if !r.state.lastHeartbeat.IsZero() {
+		lastHeartbeat := r.state.lastHeartbeat
+		health.LastHeartbeat = &lastHeartbeat
+		health.LastHeartbeatAge = time.Since(lastHeartbeat).String()
+	}
+	return health
+}
@@ -0,0 +1,334 @@
+package eureka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// registryState holds the mutable state of a Registry. It lives behind a
+// pointer so that it is shared, not copied, between copies of a Registry.
+type registryState struct {
+	mu sync.Mutex
+
+	status             InstanceStatus
+	stopHeartbeat      chan struct{}
+	attempts           int
+	conflictRecovered  bool
+	lastDirtyTimestamp int64
+	lastHeartbeat      time.Time
+	heartbeatFailures  int
+	forwardedIP        string
+	lastCheckIn        time.Time
+	throttled          int
+	aliases            []string
+
+	// zone is the Eureka server requests currently go to, once failover or a
+	// redirect moved away from DefaultZone.
+	zone string
+
+	preShutdownHooks []func(ctx context.Context)
+	inFlight         int
+	drained          chan struct{}
+
+	metadata   map[string]string
+	dataCenter *providedDataCenter
+	metrics    *metrics
+
+	dialCache     *RegistryCache
+	dialBalancers map[string]*Balancer
+}
+
+// Start registers the instance, marks it UP and sends heartbeats in the
+// background every HeartbeatInterval until Stop is called. Registration is
+// retried every RetryInterval for as long as the RetryPolicy allows, ctx is
+// not done and RegistrationDeadline has not passed.
+// Unlike Register, Start does not install a signal handler; shutting down is
+// left to the caller.
+func (r *Registry) Start(ctx context.Context) error {
+	if err := r.ValidateConfig(); err != nil {
+		return err
+	}
+	r.logger.Printf("Registering to %s to [%s:%s]\n", r.AppName, r.CurrentZone(), r.Port)
+	if err := r.registerStarting(ctx); err != nil {
+		return err
+	}
+	r.logger.Println("Successfully registered to Eureka")
+	if retry, err := r.checkInstanceIdCollision(ctx); err != nil {
+		return err
+	} else if retry {
+		return r.Start(ctx)
+	}
+
+	if err := r.SetStatus(ctx, StatusUp); err != nil {
+		return err
+	}
+	r.startHeartbeat()
+	return nil
+}
+
+// registerStarting registers the instance as STARTING, retrying until it
+// succeeds, the RetryPolicy gives up or InitOptions.RegistrationDeadline
+// passes.
+func (r *Registry) registerStarting(ctx context.Context) error {
+	ctx, cancel := r.registrationContext(ctx)
+	defer cancel()
+	for {
+		err := r.sendStatus(ctx, StatusStarting)
+		r.recordRegistration(err)
+		if err == nil {
+			r.resetAttempts()
+			return nil
+		}
+		r.logger.Printf("Error registering. %v\n", err)
+		if ctx.Err() != nil {
+			r.resetAttempts()
+			return ctx.Err()
+		}
+		var eurekaErr *EurekaError
+		if errors.As(err, &eurekaErr) && eurekaErr.StatusCode == 409 && r.recoverFromConflict(ctx) {
+			continue
+		}
+		if !r.retryable(nil, err) {
+			r.resetAttempts()
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			r.resetAttempts()
+			return ctx.Err()
+		case <-time.After(r.retryInterval()):
+		}
+	}
+}
+
+// Stop stops sending heartbeats and removes the instance from Eureka, after
+// calling the functions given to PreShutdownHook.
+func (r *Registry) Stop(ctx context.Context) error {
+	r.runPreShutdownHooks(ctx)
+
+	r.state.mu.Lock()
+	if r.state.stopHeartbeat != nil {
+		close(r.state.stopHeartbeat)
+		r.state.stopHeartbeat = nil
+	}
+	r.state.mu.Unlock()
+
+	return r.Deregister(ctx)
+}
+
+// SetStatus reports the given status of the instance to Eureka.
+func (r *Registry) SetStatus(ctx context.Context, status InstanceStatus) error {
+	if err := r.sendStatus(ctx, status); err != nil {
+		return err
+	}
+	r.logger.Printf("Successfully update status '%s' to Eureka\n", status)
+	return nil
+}
+
+// Deregister removes the instance from Eureka.
+func (r *Registry) Deregister(ctx context.Context) error {
+	url := fmt.Sprintf("%s/apps/%s/%s", r.CurrentZone(), r.AppName, r.InstanceId)
+
+	resp, err := r.deleteRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 || resp.StatusCode == 200 {
+		r.logger.Println("Successfully deregistered from Eureka")
+		r.deregisterAliases(ctx)
+		return nil
+	}
+	return newEurekaError("Deregistration", resp)
+}
+
+// DeregisterAsync deregisters the instance in the background, so a shutdown
+// can bound how long it waits for Eureka:
+//
+//	select {
+//	case err := <-r.DeregisterAsync():
+//	case <-time.After(5 * time.Second):
+//	}
+//
+// The channel receives the result of Deregister and is then closed.
+func (r *Registry) DeregisterAsync() <-chan error {
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		errc <- r.Deregister(r.baseContext())
+	}()
+	return errc
+}
+
+// ReRegister registers the instance again with its current status. The new
+// registration carries a later lastDirtyTimestamp than any previous one, so
+// it wins over a stale entry Eureka may still hold for the same instance id.
+func (r *Registry) ReRegister(ctx context.Context) error {
+	r.bumpDirtyTimestamp()
+	err := r.sendStatus(ctx, r.Status())
+	r.recordRegistration(err)
+	return err
+}
+
+// Status returns the last status successfully reported to Eureka.
+func (r *Registry) Status() InstanceStatus {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return r.state.status
+}
+
+// LastHeartbeatTime returns when the last successful heartbeat was sent,
+// the zero time if none was.
+func (r *Registry) LastHeartbeatTime() time.Time {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return r.state.lastHeartbeat
+}
+
+// LastHeartbeatAge returns how long ago the last successful heartbeat was
+// sent, zero if none was.
+func (r *Registry) LastHeartbeatAge() time.Duration {
+	last := r.LastHeartbeatTime()
+	if last.IsZero() {
+		return 0
+	}
+	return time.Since(last)
+}
+
+// sendStatus (re-)registers the instance with the given status.
+func (r *Registry) sendStatus(ctx context.Context, status InstanceStatus) error {
+	body, err := json.Marshal(r.buildBody(string(status)))
+	if err != nil {
+		return fmt.Errorf("Cannot marshal instance body. %v", err)
+	}
+
+	url := fmt.Sprintf("%s/apps/%s", r.CurrentZone(), r.AppName)
+	resp, err := r.postRequest(ctx, url, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 || resp.StatusCode == 200 {
+		r.setCurrentStatus(status)
+		r.sendAliasesStatus(ctx, status)
+		return nil
+	}
+	return newEurekaError("Registration", resp)
+}
+
+// renew sends a single heartbeat.
+func (r *Registry) renew(ctx context.Context) error {
+	url := fmt.Sprintf("%s/apps/%s/%s", r.CurrentZone(), r.AppName, r.InstanceId)
+
+	resp, err := r.putRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 || resp.StatusCode == 200 {
+		r.renewAliases(ctx)
+		return nil
+	}
+	return newEurekaError("Heartbeat", resp)
+}
+
+func (r *Registry) startHeartbeat() {
+	stop := make(chan struct{})
+
+	r.state.mu.Lock()
+	if r.state.stopHeartbeat != nil {
+		close(r.state.stopHeartbeat)
+	}
+	r.state.stopHeartbeat = stop
+	r.state.lastCheckIn = time.Now()
+	r.state.mu.Unlock()
+
+	go func() {
+		timer := time.NewTimer(r.heartbeatOffset())
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+				timer.Reset(r.heartbeatInterval())
+				if r.heartbeatAllowed() {
+					r.heartbeat()
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (r *Registry) heartbeat() {
+	ctx := r.baseContext()
+	err := r.renew(ctx)
+	r.recordHeartbeat(err)
+	if err == nil {
+		if r.opt.Verbose {
+			r.logger.Println("Heartbeat to Eureka [OK]")
+		}
+		return
+	}
+	r.logger.Println(fmt.Errorf("Heartbeat to Eureka [FAILED]. %v", err))
+
+	var eurekaErr *EurekaError
+	if !errors.As(err, &eurekaErr) || eurekaErr.StatusCode != 404 {
+		return
+	}
+	instance, err := r.ownInstance(ctx)
+	switch {
+	case err != nil:
+		r.logger.Printf("Cannot check registration, not re-registering. %v\n", err)
+	case instance == nil:
+		// Eureka has forgotten about us, most likely because the lease expired.
+		if err := r.ReRegister(ctx); err != nil {
+			r.logger.Printf("Error registering. %v\n", err)
+		}
+	case instance.Status == string(StatusDown):
+		if err := r.SetStatus(ctx, StatusUp); err != nil {
+			r.logger.Printf("Error sending UP status. %v\n", err)
+		}
+	}
+}
+
+// ownInstance looks this instance up after a heartbeat was answered with 404.
+// It returns a nil instance only when Eureka confirms the instance is gone,
+// so that a transient failure does not lead to a duplicate registration.
+func (r *Registry) ownInstance(ctx context.Context) (*InstanceDetails, error) {
+	instance, err := r.GetInstanceByID(ctx, r.AppName, r.InstanceId)
+	var eurekaErr *EurekaError
+	if errors.As(err, &eurekaErr) && eurekaErr.StatusCode == 404 {
+		return nil, nil
+	}
+	return instance, err
+}
+
+// dirtyTimestamp returns the lastDirtyTimestamp to register with: the
+// current time in Unix milliseconds, never going backwards.
+func (r *Registry) dirtyTimestamp() int64 {
+	now := time.Now().UnixMilli()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	if now > r.state.lastDirtyTimestamp {
+		r.state.lastDirtyTimestamp = now
+	}
+	return r.state.lastDirtyTimestamp
+}
+
+// bumpDirtyTimestamp makes the next registration carry a lastDirtyTimestamp
+// strictly later than the previous one.
+func (r *Registry) bumpDirtyTimestamp() {
+	r.state.mu.Lock()
+	r.state.lastDirtyTimestamp++
+	r.state.mu.Unlock()
+}
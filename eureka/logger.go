@@ -0,0 +1,38 @@
+package eureka
+
+import "fmt"
+
+// Logger is what a Registry logs through. *log.Logger satisfies it; so does
+// the adapter returned by NewSlogLogger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+	Println(v ...interface{})
+}
+
+// WithLogger sends the log output of the registry to l.
+func WithLogger(l Logger) Option {
+	return func(r *Registry) error {
+		r.opt.Logger = l
+		return nil
+	}
+}
+
+// Logger returns the logger of the registry, prefixing messages with its
+// LogPrefix, for packages working on behalf of the registry to log through.
+func (r *Registry) Logger() Logger {
+	return r.logger
+}
+
+// prefixLogger prepends prefix to every message logged through next.
+type prefixLogger struct {
+	next   Logger
+	prefix string
+}
+
+func (l *prefixLogger) Printf(format string, v ...interface{}) {
+	l.next.Printf("%s%s", l.prefix, fmt.Sprintf(format, v...))
+}
+
+func (l *prefixLogger) Println(v ...interface{}) {
+	l.next.Printf("%s%s", l.prefix, fmt.Sprintln(v...))
+}
@@ -0,0 +1,11 @@
+package eureka
+
+// NewEurekaWithServiceMesh returns a Registry like NewEureka, with every
+// call to Eureka sent through the service mesh sidecar listening at
+// meshProxy, e.g. "http://127.0.0.1:15001", as an HTTP proxy. Requests
+// still address the canonical eurekaServerUrl, so the mesh routes them, and
+// the proxy is set on this registry's transport only, leaving the other
+// HTTP clients of the process alone.
+func NewEurekaWithServiceMesh(eurekaServerUrl, appname, meshProxy string, opts ...Option) (*Registry, error) {
+	return NewEureka(eurekaServerUrl, appname, nil, append([]Option{WithProxy(meshProxy)}, opts...)...)
+}
@@ -0,0 +1,159 @@
+package eureka
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// UpdateMetadata sets a metadata key of this instance in Eureka without
+// re-registering it, so the instance stays available while its metadata
+// changes. The key is also kept for subsequent registrations.
+func (r *Registry) UpdateMetadata(ctx context.Context, key, value string) error {
+	query := url.Values{r.opt.MetadataNamespacePrefix + key: {value}}
+	endpoint := fmt.Sprintf("%s/apps/%s/%s/metadata?%s", r.CurrentZone(), r.AppName, r.InstanceId, query.Encode())
+
+	resp, err := r.putRequest(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 && resp.StatusCode != 200 {
+		return newEurekaError("Updating metadata", resp)
+	}
+
+	r.state.mu.Lock()
+	if r.state.metadata == nil {
+		r.state.metadata = make(map[string]string)
+	}
+	r.state.metadata[key] = value
+	r.state.mu.Unlock()
+
+	r.logger.Printf("Successfully update metadata '%s' to Eureka\n", key)
+	return nil
+}
+
+// GetMetadata fetches the metadata Eureka currently stores for this instance.
+func (r *Registry) GetMetadata(ctx context.Context) (map[string]string, error) {
+	instance, err := r.GetInstanceByID(ctx, r.AppName, r.InstanceId)
+	if err != nil {
+		return nil, err
+	}
+	return r.instanceMetadata(instance), nil
+}
+
+// GetInstanceMetadata fetches the metadata Eureka currently stores for any
+// instance, read the same way as GetMetadata.
+func (r *Registry) GetInstanceMetadata(ctx context.Context, appName, instanceId string) (map[string]string, error) {
+	instance, err := r.GetInstanceByID(ctx, appName, instanceId)
+	if err != nil {
+		return nil, err
+	}
+	return r.instanceMetadata(instance), nil
+}
+
+// SetInstanceMetadata sets a metadata key of any instance in Eureka, with
+// MetadataNamespacePrefix prepended like UpdateMetadata does. The change
+// lasts until that instance registers again with its own metadata.
+func (r *Registry) SetInstanceMetadata(ctx context.Context, appName, instanceId, key, value string) error {
+	query := url.Values{r.opt.MetadataNamespacePrefix + key: {value}}
+	endpoint := fmt.Sprintf("%s/apps/%s/%s/metadata?%s", r.CurrentZone(), r.normalizeAppName(appName), instanceId, query.Encode())
+
+	resp, err := r.putRequest(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 && resp.StatusCode != 200 {
+		return newEurekaError("Updating metadata", resp)
+	}
+	return nil
+}
+
+// instanceMetadata returns the metadata of instance without the "@class"
+// marker Eureka adds to empty metadata maps, and with MetadataNamespacePrefix
+// stripped from the keys. A namespaced key wins over a bare key of the same
+// name.
+func (r *Registry) instanceMetadata(instance *InstanceDetails) map[string]string {
+	prefix := r.opt.MetadataNamespacePrefix
+	metadata := make(map[string]string, len(instance.Metadata))
+	for k, v := range instance.Metadata {
+		if k == "@class" {
+			continue
+		}
+		if prefix != "" && strings.HasPrefix(k, prefix) {
+			continue
+		}
+		metadata[k] = v
+	}
+	if prefix != "" {
+		for k, v := range instance.Metadata {
+			if strings.HasPrefix(k, prefix) {
+				metadata[strings.TrimPrefix(k, prefix)] = v
+			}
+		}
+	}
+	return metadata
+}
+
+// registrationMetadata returns the metadata sent on registration: the keys
+// derived from the configuration, overridden by the explicitly set ones, all
+// prefixed with MetadataNamespacePrefix.
+func (r *Registry) registrationMetadata(dataCenterMetadata map[string]string) map[string]string {
+	metadata := make(map[string]string)
+	for k, v := range dataCenterMetadata {
+		metadata[k] = v
+	}
+	if r.opt.Environment != "" {
+		metadata[environmentMetadataKey] = r.opt.Environment
+	}
+	if r.opt.IsSandboxApp {
+		metadata[sandboxMetadataKey] = "true"
+	}
+	if r.opt.ASGName != "" {
+		metadata[asgMetadataKey] = r.opt.ASGName
+	}
+	if r.opt.SelfZone != "" {
+		metadata[zoneMetadataKey] = r.opt.SelfZone
+	}
+	weight := r.opt.InstanceWeight
+	if !validWeight(weight) {
+		weight = 1
+	}
+	metadata[WeightMetadataKey] = strconv.FormatFloat(weight, 'g', -1, 64)
+	if len(r.opt.Dependencies) > 0 {
+		metadata[DependenciesMetadataKey] = strings.Join(r.opt.Dependencies, ",")
+	}
+	for k, v := range r.metadataSnapshot() {
+		metadata[k] = v
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	if prefix := r.opt.MetadataNamespacePrefix; prefix != "" {
+		namespaced := make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			namespaced[prefix+k] = v
+		}
+		metadata = namespaced
+	}
+	return metadata
+}
+
+// metadataSnapshot returns a copy of the metadata sent on registration.
+func (r *Registry) metadataSnapshot() map[string]string {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	if len(r.state.metadata) == 0 {
+		return nil
+	}
+	metadata := make(map[string]string, len(r.state.metadata))
+	for k, v := range r.state.metadata {
+		metadata[k] = v
+	}
+	return metadata
+}
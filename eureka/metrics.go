@@ -0,0 +1,96 @@
+package eureka
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// metrics are the counters of a registry published through expvar, and thus
+// served by the /debug/vars endpoint of net/http/pprof.
+//
+// The first app registering in the process publishes them under plain names
+// like "eureka.heartbeats.total". Any other app prefixes them with its name,
+// e.g. "MY_OTHER_APP.eureka.heartbeats.total". Registries of the same app,
+// e.g. one per Eureka cluster, share the same counters.
+type metrics struct {
+	heartbeats          *expvar.Int
+	heartbeatsFailed    *expvar.Int
+	registrations       *expvar.Int
+	registrationsFailed *expvar.Int
+	status              *expvar.String
+}
+
+var (
+	metricsMu       sync.Mutex
+	plainMetricsApp string
+)
+
+func newMetrics(appName string) *metrics {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	prefix := "eureka."
+	if plainMetricsApp == "" {
+		plainMetricsApp = appName
+	} else if plainMetricsApp != appName {
+		prefix = appName + ".eureka."
+	}
+
+	return &metrics{
+		heartbeats:          publishInt(prefix + "heartbeats.total"),
+		heartbeatsFailed:    publishInt(prefix + "heartbeats.failed"),
+		registrations:       publishInt(prefix + "registrations.total"),
+		registrationsFailed: publishInt(prefix + "registrations.failed"),
+		status:              publishString(prefix + "current_status"),
+	}
+}
+
+// publishInt returns the expvar.Int published as name, publishing it first
+// if needed. The caller must hold metricsMu.
+func publishInt(name string) *expvar.Int {
+	if v, ok := expvar.Get(name).(*expvar.Int); ok {
+		return v
+	}
+	return expvar.NewInt(name)
+}
+
+// publishString returns the expvar.String published as name, publishing it
+// first if needed. The caller must hold metricsMu.
+func publishString(name string) *expvar.String {
+	if v, ok := expvar.Get(name).(*expvar.String); ok {
+		return v
+	}
+	return expvar.NewString(name)
+}
+
+func (r *Registry) recordRegistration(err error) {
+	r.state.metrics.registrations.Add(1)
+	if err != nil {
+		r.state.metrics.registrationsFailed.Add(1)
+	}
+}
+
+func (r *Registry) recordHeartbeat(err error) {
+	r.state.metrics.heartbeats.Add(1)
+	if err != nil {
+		r.state.metrics.heartbeatsFailed.Add(1)
+	}
+
+	r.state.mu.Lock()
+	if err != nil {
+		r.state.heartbeatFailures++
+	} else {
+		r.state.heartbeatFailures = 0
+		r.state.lastHeartbeat = time.Now()
+	}
+	r.state.mu.Unlock()
+}
+
+// setCurrentStatus records the status last reported to Eureka.
+func (r *Registry) setCurrentStatus(status InstanceStatus) {
+	r.state.mu.Lock()
+	r.state.status = status
+	r.state.mu.Unlock()
+	r.state.metrics.status.Set(string(status))
+}
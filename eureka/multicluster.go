@@ -0,0 +1,82 @@
+package eureka
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// MultiClusterRegistry registers the same service in several Eureka clusters
+// at once, e.g. one per region in an active-active deployment. Clusters are
+// called concurrently, at most as many at a time as the lowest
+// InitOptions.MaxConcurrentRequests of the registries.
+type MultiClusterRegistry struct {
+	registries []*Registry
+}
+
+func NewMultiClusterRegistry(registries []*Registry) *MultiClusterRegistry {
+	return &MultiClusterRegistry{registries: registries}
+}
+
+// Start starts every underlying registry concurrently. The first error from
+// any cluster is returned, but the other clusters still finish starting.
+func (m *MultiClusterRegistry) Start(ctx context.Context) error {
+	return m.each(func(r *Registry) error {
+		return r.Start(ctx)
+	})
+}
+
+// Stop stops every underlying registry concurrently. The first error from
+// any cluster is returned, but the other clusters still finish stopping.
+func (m *MultiClusterRegistry) Stop(ctx context.Context) error {
+	return m.each(func(r *Registry) error {
+		return r.Stop(ctx)
+	})
+}
+
+// SetStatus reports status to every cluster concurrently. The first error
+// from any cluster is returned, but the other clusters are still updated.
+func (m *MultiClusterRegistry) SetStatus(ctx context.Context, status InstanceStatus) error {
+	return m.each(func(r *Registry) error {
+		return r.SetStatus(ctx, status)
+	})
+}
+
+// GetApplication queries the clusters in order and returns the answer of the
+// first one that responds successfully.
+func (m *MultiClusterRegistry) GetApplication(ctx context.Context, appName string) (*Application, error) {
+	if len(m.registries) == 0 {
+		return nil, errors.New("No Eureka cluster configured")
+	}
+	var err error
+	for _, r := range m.registries {
+		var app *Application
+		app, err = r.GetApplication(ctx, appName)
+		if err == nil {
+			return app, nil
+		}
+	}
+	return nil, err
+}
+
+func (m *MultiClusterRegistry) each(fn func(r *Registry) error) error {
+	limit := defaultMaxConcurrentRequests
+	for i, r := range m.registries {
+		if n := r.maxConcurrentRequests(); i == 0 || n < limit {
+			limit = n
+		}
+	}
+
+	sem := make(chan struct{}, limit)
+	var g errgroup.Group
+	for _, r := range m.registries {
+		r := r
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			return fn(r)
+		})
+	}
+	return g.Wait()
+}
@@ -0,0 +1,132 @@
+package eureka
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Option configures a Registry created by NewEureka. Options are applied on
+// top of the InitOptions passed to NewEureka. An option rejecting its
+// arguments returns an error, which NewEureka fails with.
+type Option func(r *Registry) error
+
+// WithHeartbeatInterval sets how often a heartbeat is sent. The interval
+// must be positive.
+func WithHeartbeatInterval(d time.Duration) Option {
+	return func(r *Registry) error {
+		if d <= 0 {
+			return errors.New("Heartbeat interval must be positive")
+		}
+		r.opt.HeartbeatInterval = d
+		return nil
+	}
+}
+
+// WithTLSConfig sets the base TLS config for connections to Eureka. A nil
+// config leaves the default in place. It cannot be combined with WithH2C.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(r *Registry) error {
+		if config == nil {
+			return nil
+		}
+		if r.opt.H2C {
+			return errors.New("TLS config cannot be combined with h2c")
+		}
+		r.opt.TLSConfig = config
+		return nil
+	}
+}
+
+// WithH2C talks HTTP/2 to an http:// Eureka server over plain TCP, sparing
+// services with a high heartbeat rate the overhead of HTTP/1.1 connections.
+// It cannot be combined with WithTLSConfig: over TLS, HTTP/2 is negotiated
+// through ALPN anyway.
+func WithH2C() Option {
+	return func(r *Registry) error {
+		if r.opt.TLSConfig != nil {
+			return errors.New("h2c cannot be combined with a TLS config")
+		}
+		r.opt.H2C = true
+		return nil
+	}
+}
+
+// WithSpringBootPaths sets the paths of the registered home page, health
+// check and status page URLs at once, e.g. for a custom layout. An empty
+// path leaves the default in place.
+func WithSpringBootPaths(home, health, status string) Option {
+	return func(r *Registry) error {
+		r.opt.HomePagePath = home
+		r.opt.HealthCheckPath = health
+		r.opt.StatusPagePath = status
+		return nil
+	}
+}
+
+// WithSpringBootActuator registers the page URLs of the Spring Boot
+// Actuator conventions: the health check at /actuator/health, the status
+// page at /actuator/info and the home page at /, as expected by a Spring
+// Cloud Eureka infrastructure.
+func WithSpringBootActuator() Option {
+	return func(r *Registry) error {
+		return WithSpringBootPaths("/", "/actuator/health", "/actuator/info")(r)
+	}
+}
+
+// WithProxy sends all calls to Eureka through the HTTP proxy at proxyUrl.
+func WithProxy(proxyUrl string) Option {
+	return func(r *Registry) error {
+		u, err := url.Parse(proxyUrl)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("Invalid proxy URL %q", proxyUrl)
+		}
+		r.opt.ProxyURL = proxyUrl
+		return nil
+	}
+}
+
+// WithDNSCacheTTL sets how long resolved Eureka server addresses are cached.
+// A negative TTL disables the cache, leaving every lookup to the system resolver.
+func WithDNSCacheTTL(d time.Duration) Option {
+	return func(r *Registry) error {
+		r.opt.DNSCacheTTL = d
+		return nil
+	}
+}
+
+// WithEnvironment tags the instance with its deployment environment.
+func WithEnvironment(env string) Option {
+	return func(r *Registry) error {
+		r.opt.Environment = env
+		return nil
+	}
+}
+
+// WithPreserveAppNameCase opts out of uppercasing app names.
+func WithPreserveAppNameCase() Option {
+	return func(r *Registry) error {
+		r.opt.PreserveAppNameCase = true
+		return nil
+	}
+}
+
+// WithCoordinatingServer sets the isCoordinatingDiscoveryServer flag of the
+// registration.
+func WithCoordinatingServer(coordinating bool) Option {
+	return func(r *Registry) error {
+		r.opt.IsCoordinatingDiscoveryServer = coordinating
+		return nil
+	}
+}
+
+// WithSandbox registers the instance as a sandbox (test) instance, which
+// balancers only route to when asked to prefer sandbox instances.
+func WithSandbox() Option {
+	return func(r *Registry) error {
+		r.opt.IsSandboxApp = true
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+package eureka
+
+import (
+	"context"
+	"sync"
+)
+
+// Track records a request the service starts serving, which Pause waits for
+// before returning. Call done once it is served; later calls are no-ops.
+func (r *Registry) Track() (done func()) {
+	r.state.mu.Lock()
+	r.state.inFlight++
+	r.state.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.state.mu.Lock()
+			defer r.state.mu.Unlock()
+			r.state.inFlight--
+			if r.state.inFlight == 0 && r.state.drained != nil {
+				close(r.state.drained)
+				r.state.drained = nil
+			}
+		})
+	}
+}
+
+// drained returns a channel closed once no request recorded by Track is in
+// flight.
+func (r *Registry) drained() <-chan struct{} {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	if r.state.inFlight == 0 {
+		drained := make(chan struct{})
+		close(drained)
+		return drained
+	}
+	if r.state.drained == nil {
+		r.state.drained = make(chan struct{})
+	}
+	return r.state.drained
+}
+
+// Pause takes the instance out of rotation for a rolling restart: its status
+// becomes PAUSING, Eureka is told OUT_OF_SERVICE through a status override,
+// then Pause waits until the requests recorded by Track have drained or ctx
+// is done.
+func (r *Registry) Pause(ctx context.Context) error {
+	previous := r.Status()
+	r.setCurrentStatus(StatusPausing)
+	if err := r.OverrideStatus(ctx, StatusOutOfService); err != nil {
+		r.setCurrentStatus(previous)
+		return err
+	}
+
+	select {
+	case <-r.drained():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Resume puts a paused instance back in rotation, removing the status
+// override and reporting the instance UP again.
+func (r *Registry) Resume(ctx context.Context) error {
+	if err := r.DeleteStatusOverride(ctx); err != nil {
+		return err
+	}
+	return r.SetStatus(ctx, StatusUp)
+}
+
+// wireStatus returns the status to send Eureka for status, which has no
+// notion of PAUSING.
+func wireStatus(status string) string {
+	if status == string(StatusPausing) {
+		return string(StatusOutOfService)
+	}
+	return status
+}
@@ -0,0 +1,67 @@
+package eureka_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"github.com/abetobing/go-eureka/server"
+)
+
+func TestPauseWaitsForTrackedRequests(t *testing.T) {
+	ts := httptest.NewServer(server.NewEurekaServer())
+	defer ts.Close()
+
+	r, err := eureka.NewEureka(ts.URL, "APP", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop(ctx)
+
+	// Pausing with nothing in flight returns right away, repeatedly.
+	for i := 0; i < 2; i++ {
+		if err := r.Pause(ctx); err != nil {
+			t.Fatalf("Pause with no request in flight: %v", err)
+		}
+	}
+
+	done := r.Track()
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := r.Pause(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Pause with a request in flight = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	paused := make(chan error, 1)
+	go func() { paused <- r.Pause(ctx) }()
+	select {
+	case err := <-paused:
+		t.Fatalf("Pause returned %v before the request was done", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	done()
+	done()
+	select {
+	case err := <-paused:
+		if err != nil {
+			t.Fatalf("Pause after the request was done: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Pause did not return once the request was done")
+	}
+
+	instance, err := r.GetInstanceByID(ctx, "APP", r.InstanceId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instance.Status != string(eureka.StatusOutOfService) {
+		t.Errorf("status = %s, want OUT_OF_SERVICE", instance.Status)
+	}
+}
@@ -0,0 +1,105 @@
+package eureka
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// InstancePool keeps the UP instances of a set of applications cached, all
+// refreshed together every ttl by a single background poller, for services
+// calling many downstream services. Close stops the poller.
+type InstancePool struct {
+	r        *Registry
+	appNames []string
+	ttl      time.Duration
+
+	mu        sync.RWMutex
+	instances map[string][]InstanceDetails
+	strategy  RoundRobinStrategy
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewInstancePool returns a pool of the instances of appNames, fetched once
+// before it returns and then every ttl, 30 seconds when zero.
+func NewInstancePool(r *Registry, appNames []string, ttl time.Duration) *InstancePool {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	p := &InstancePool{
+		r:         r,
+		ttl:       ttl,
+		instances: make(map[string][]InstanceDetails),
+		stop:      make(chan struct{}),
+	}
+	for _, appName := range appNames {
+		appName = r.normalizeAppName(appName)
+		p.appNames = append(p.appNames, appName)
+		p.instances[appName] = nil
+	}
+
+	p.refresh(r.baseContext())
+	go p.poll()
+	return p
+}
+
+// Pick returns one of the cached UP instances of the application, in round
+// robin. It fails with ErrApplicationNotFound for an application outside the
+// pool and ErrNoInstances when none of its instances is UP.
+func (p *InstancePool) Pick(appName string) (*InstanceDetails, error) {
+	p.mu.RLock()
+	instances, ok := p.instances[p.r.normalizeAppName(appName)]
+	p.mu.RUnlock()
+	if !ok {
+		return nil, ErrApplicationNotFound
+	}
+
+	instance := p.strategy.Select(instances)
+	if instance == nil {
+		return nil, ErrNoInstances
+	}
+	return instance, nil
+}
+
+// Close stops refreshing the pool. The cached instances stay available.
+func (p *InstancePool) Close() {
+	p.once.Do(func() { close(p.stop) })
+}
+
+func (p *InstancePool) poll() {
+	ticker := time.NewTicker(p.ttl)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.refresh(p.r.baseContext())
+		}
+	}
+}
+
+// refresh fetches the full registry once and keeps the UP instances of the
+// applications of the pool. On failure the previous instances are kept.
+func (p *InstancePool) refresh(ctx context.Context) {
+	apps, err := p.r.GetApplications(ctx)
+	if err != nil {
+		p.r.logger.Println("Can't refresh instance pool. Using stale instances.", err)
+		return
+	}
+
+	byName := make(map[string][]InstanceDetails, len(apps.Application))
+	for _, app := range apps.Application {
+		byName[p.r.normalizeAppName(app.Name)] = filterByStatus(app.Instance, StatusUp)
+	}
+	instances := make(map[string][]InstanceDetails, len(p.appNames))
+	for _, appName := range p.appNames {
+		instances[appName] = byName[appName]
+	}
+
+	p.mu.Lock()
+	p.instances = instances
+	p.mu.Unlock()
+}
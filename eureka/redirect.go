@@ -0,0 +1,59 @@
+package eureka
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxRedirectHops bounds how many times a single request follows a Eureka
+// server redirecting it to another node.
+const maxRedirectHops = 3
+
+// checkRedirect leaves the redirects Eureka allows to switch servers to
+// doRequest, and follows the others as http.Client does by default.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if req.Response != nil && allowsRedirect(req.Response) {
+		return http.ErrUseLastResponse
+	}
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return nil
+}
+
+func allowsRedirect(resp *http.Response) bool {
+	return strings.EqualFold(resp.Header.Get("X-Discovery-Allow-Redirect"), "true")
+}
+
+// redirectedZone returns the Eureka server URL a response with
+// X-Discovery-Allow-Redirect: true sends the client to, taken from its
+// Location. The path of the request relative to current, the zone it was
+// sent to, is stripped from the location; when the location does not end
+// with it, the location keeps the path of current on the new host.
+func (r *Registry) redirectedZone(resp *http.Response, current, requestUrl string) (string, bool) {
+	if !allowsRedirect(resp) {
+		return "", false
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return "", false
+	}
+	zone, err := url.Parse(current)
+	if err != nil {
+		return "", false
+	}
+	request, err := url.Parse(requestUrl)
+	if err != nil {
+		return "", false
+	}
+
+	suffix := strings.TrimPrefix(request.Path, zone.Path)
+	path := zone.Path
+	if suffix != "" && strings.HasSuffix(location.Path, suffix) {
+		path = strings.TrimSuffix(location.Path, suffix)
+	}
+	redirected := url.URL{Scheme: location.Scheme, User: location.User, Host: location.Host, Path: path}
+	return strings.TrimSuffix(redirected.String(), "/"), true
+}
@@ -0,0 +1,94 @@
+package eureka_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"github.com/abetobing/go-eureka/server"
+)
+
+// redirectingServer redirects every request to the same path on the server
+// at target, allowing the client to switch servers when allow is set.
+func redirectingServer(target func() string, allow bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if allow {
+			w.Header().Set("X-Discovery-Allow-Redirect", "true")
+		}
+		http.Redirect(w, req, target()+req.URL.RequestURI(), http.StatusFound)
+	})
+}
+
+func TestRedirectHops(t *testing.T) {
+	live := newCountingServer(server.NewEurekaServer())
+	defer live.Close()
+
+	tests := []struct {
+		name       string
+		handler    func(self func() string) http.Handler
+		wantStatus int
+		wantHits   int
+		wantZone   func(self string) string
+		wantLive   int
+	}{
+		{
+			name: "allowed redirect switches zone",
+			handler: func(func() string) http.Handler {
+				return redirectingServer(live.zone, true)
+			},
+			wantHits: 1,
+			wantZone: func(string) string { return live.zone() },
+			wantLive: 1,
+		},
+		{
+			name: "plain redirect is followed without switching zone",
+			handler: func(func() string) http.Handler {
+				return redirectingServer(live.zone, false)
+			},
+			wantHits: 1,
+			wantZone: func(self string) string { return self },
+			wantLive: 1,
+		},
+		{
+			name: "redirect loop stops after the maximum hops",
+			handler: func(self func() string) http.Handler {
+				return redirectingServer(self, true)
+			},
+			wantStatus: http.StatusFound,
+			wantHits:   4,
+			wantZone:   func(self string) string { return self },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var redirecting *countingServer
+			redirecting = newCountingServer(tt.handler(func() string { return redirecting.zone() }))
+			defer redirecting.Close()
+			liveBefore := live.count()
+
+			r, err := eureka.NewEureka(redirecting.zone(), "APP", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = r.GetApplications(context.Background())
+			var eurekaErr *eureka.EurekaError
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Fatalf("GetApplications() error = %v", err)
+			case tt.wantStatus != 0 && (!errors.As(err, &eurekaErr) || eurekaErr.StatusCode != tt.wantStatus):
+				t.Fatalf("GetApplications() error = %v, want status %d", err, tt.wantStatus)
+			}
+			if hits := redirecting.count(); hits != tt.wantHits {
+				t.Errorf("redirecting server got %d requests, want %d", hits, tt.wantHits)
+			}
+			if hits := live.count() - liveBefore; hits != tt.wantLive {
+				t.Errorf("live server got %d requests, want %d", hits, tt.wantLive)
+			}
+			if zone, want := r.CurrentZone(), tt.wantZone(redirecting.zone()); zone != want {
+				t.Errorf("CurrentZone() = %q, want %q", zone, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package eureka
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RegistryPool registers several apps to the same Eureka server from one
+// process, e.g. a gateway representing several backends, one Registry per
+// app.
+type RegistryPool struct {
+	registries []*Registry
+}
+
+// NewRegistryPool returns a pool with a Registry for each of apps on the
+// Eureka server at eurekaServerUrl, all configured by opts. Call Start to
+// register them. It fails when two apps share a name.
+func NewRegistryPool(eurekaServerUrl string, apps []string, opts ...Option) (*RegistryPool, error) {
+	p := &RegistryPool{}
+	for _, app := range apps {
+		r, err := NewEureka(eurekaServerUrl, app, nil, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.Get(r.AppName); err == nil {
+			return nil, fmt.Errorf("App %s is in the pool more than once", r.AppName)
+		}
+		p.registries = append(p.registries, r)
+	}
+	return p, nil
+}
+
+// Start starts every registry concurrently. The first error from any app is
+// returned, and the context of the other apps is cancelled. The apps
+// already started by then are stopped with ctx, so that a pool failing to
+// start is not left half running.
+func (p *RegistryPool) Start(ctx context.Context) error {
+	started := make([]bool, len(p.registries))
+	g, startCtx := errgroup.WithContext(ctx)
+	for i, r := range p.registries {
+		i, r := i, r
+		g.Go(func() error {
+			if err := r.Start(startCtx); err != nil {
+				return err
+			}
+			started[i] = true
+			return nil
+		})
+	}
+	err := g.Wait()
+	if err == nil {
+		return nil
+	}
+
+	var running []*Registry
+	for i, r := range p.registries {
+		if started[i] {
+			running = append(running, r)
+		}
+	}
+	if stopErr := stopAll(ctx, running); stopErr != nil {
+		running[0].logger.Printf("Cannot stop the apps started before the pool failed to start. %v\n", stopErr)
+	}
+	return err
+}
+
+// Stop stops every registry concurrently. The first error from any app is
+// returned, but the other apps still finish stopping.
+func (p *RegistryPool) Stop(ctx context.Context) error {
+	return stopAll(ctx, p.registries)
+}
+
+func stopAll(ctx context.Context, registries []*Registry) error {
+	var g errgroup.Group
+	for _, r := range registries {
+		r := r
+		g.Go(func() error {
+			return r.Stop(ctx)
+		})
+	}
+	return g.Wait()
+}
+
+// Get returns the registry of appName.
+func (p *RegistryPool) Get(appName string) (*Registry, error) {
+	for _, r := range p.registries {
+		if r.AppName == r.normalizeAppName(appName) {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("No registry for app %s in the pool", appName)
+}
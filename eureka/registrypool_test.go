@@ -0,0 +1,51 @@
+package eureka_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"github.com/abetobing/go-eureka/server"
+)
+
+func TestNewRegistryPoolRejectsDuplicateApps(t *testing.T) {
+	_, err := eureka.NewRegistryPool("http://eureka.test/eureka", []string{"orders", "ORDERS"})
+	if err == nil {
+		t.Fatal("NewRegistryPool accepted the same app twice")
+	}
+}
+
+func TestRegistryPoolStartFailureStopsStartedApps(t *testing.T) {
+	srv := server.NewEurekaServer()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost && req.URL.Path == "/apps/BROKEN" {
+			// Fail once the other app is up.
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		srv.ServeHTTP(w, req)
+	}))
+	defer ts.Close()
+
+	p, err := eureka.NewRegistryPool(ts.URL, []string{"HEALTHY", "BROKEN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := p.Start(ctx); err == nil {
+		p.Stop(ctx)
+		t.Fatal("Start succeeded although an app failed to register")
+	}
+
+	healthy, err := p.Get("HEALTHY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := healthy.GetApplication(ctx, "HEALTHY"); err == nil {
+		t.Error("HEALTHY is still registered after the pool failed to start")
+	}
+}
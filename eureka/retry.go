@@ -0,0 +1,78 @@
+package eureka
+
+import (
+	"errors"
+	"net/http"
+)
+
+// defaultNonRetryableStatusCodes are the credential errors, which asking
+// again will not fix.
+var defaultNonRetryableStatusCodes = []int{401, 403}
+
+// RetryPolicy decides whether a failed call to Eureka is worth retrying.
+// resp is nil when the request failed before a response was received, in
+// which case err holds the transport error. attempt starts at 1.
+type RetryPolicy interface {
+	ShouldRetry(resp *http.Response, err error, attempt int) bool
+}
+
+// DefaultRetryPolicy retries network errors and 5xx responses, but not 4xx
+// responses: a 409 Conflict or a 401 Unauthorized will not go away by asking
+// again. A 404 on a heartbeat is not retried either, because it means Eureka
+// has dropped the instance; the heartbeat re-registers it instead.
+//
+// MaxAttempts bounds the number of attempts, zero meaning no bound.
+type DefaultRetryPolicy struct {
+	MaxAttempts int
+}
+
+func (p DefaultRetryPolicy) ShouldRetry(resp *http.Response, err error, attempt int) bool {
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return false
+	}
+	if resp == nil {
+		return err != nil
+	}
+	return resp.StatusCode >= 500
+}
+
+// retryable counts a failed attempt and reports whether the configured
+// RetryPolicy wants it retried.
+func (r *Registry) retryable(resp *http.Response, err error) bool {
+	var eurekaErr *EurekaError
+	if errors.As(err, &eurekaErr) {
+		resp, err = eurekaErr.resp, nil
+	}
+	if resp != nil && r.nonRetryable(resp.StatusCode) {
+		return false
+	}
+
+	r.state.mu.Lock()
+	r.state.attempts++
+	attempt := r.state.attempts
+	r.state.mu.Unlock()
+
+	return r.retryPolicy.ShouldRetry(resp, err, attempt)
+}
+
+// nonRetryable tells whether statusCode is one of
+// InitOptions.NonRetryableStatusCodes.
+func (r *Registry) nonRetryable(statusCode int) bool {
+	codes := r.opt.NonRetryableStatusCodes
+	if codes == nil {
+		codes = defaultNonRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Registry) resetAttempts() {
+	r.state.mu.Lock()
+	r.state.attempts = 0
+	r.state.conflictRecovered = false
+	r.state.mu.Unlock()
+}
@@ -0,0 +1,9 @@
+package eureka
+
+const sandboxMetadataKey = "isSandboxApp"
+
+// isSandbox tells whether instance is a sandbox instance. Eureka servers drop
+// unknown instance fields, so the metadata key is checked as well.
+func (r *Registry) isSandbox(instance *InstanceDetails) bool {
+	return instance.IsSandboxApp || r.instanceMetadata(instance)[sandboxMetadataKey] == "true"
+}
@@ -0,0 +1,32 @@
+package eureka
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// NewSecureEureka returns a Registry like NewEureka that only ever talks to
+// Eureka over verified HTTPS: eurekaHTTPSURL must use the https scheme, and
+// tlsConfig, the base TLS config of the connections, must not skip
+// certificate verification. A nil tlsConfig verifies the server against the
+// system cert pool. The secure port of the instance is enabled as well.
+func NewSecureEureka(eurekaHTTPSURL, appname string, tlsConfig *tls.Config, opts ...Option) (*Registry, error) {
+	zone, err := url.Parse(eurekaHTTPSURL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Eureka server URL %q. %v", eurekaHTTPSURL, err)
+	}
+	if zone.Scheme != "https" {
+		return nil, fmt.Errorf("Invalid Eureka server URL %q. Scheme must be https", eurekaHTTPSURL)
+	}
+	if tlsConfig != nil && tlsConfig.InsecureSkipVerify {
+		return nil, errors.New("TLS config must verify the certificate of the Eureka server")
+	}
+
+	secure := func(r *Registry) error {
+		r.opt.SecurePortEnabled = true
+		return nil
+	}
+	return NewEureka(eurekaHTTPSURL, appname, nil, append([]Option{WithTLSConfig(tlsConfig), secure}, opts...)...)
+}
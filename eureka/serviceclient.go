@@ -0,0 +1,155 @@
+package eureka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const defaultServiceClientAttempts = 3
+
+// ServiceClient calls the instances of another application discovered via
+// Eureka. Each request goes to an instance picked by the load balancing
+// strategy and is retried on a different instance when it fails.
+type ServiceClient struct {
+	appName     string
+	cache       *RegistryCache
+	strategy    LoadBalancingStrategy
+	client      *http.Client
+	maxAttempts int
+}
+
+type ServiceClientOption func(c *ServiceClient)
+
+// WithLoadBalancingStrategy sets how instances are picked. Defaults to round robin.
+func WithLoadBalancingStrategy(strategy LoadBalancingStrategy) ServiceClientOption {
+	return func(c *ServiceClient) {
+		c.strategy = strategy
+	}
+}
+
+// WithRegistryCache makes the client look instances up in cache, so several
+// clients can share a single copy of the registry.
+func WithRegistryCache(cache *RegistryCache) ServiceClientOption {
+	return func(c *ServiceClient) {
+		c.cache = cache
+	}
+}
+
+// WithServiceHTTPClient sets the HTTP client used to call the instances.
+// Defaults to http.DefaultClient.
+func WithServiceHTTPClient(client *http.Client) ServiceClientOption {
+	return func(c *ServiceClient) {
+		c.client = client
+	}
+}
+
+// WithMaxAttempts sets how many instances a request is tried on before
+// giving up. Defaults to 3.
+func WithMaxAttempts(n int) ServiceClientOption {
+	return func(c *ServiceClient) {
+		c.maxAttempts = n
+	}
+}
+
+// NewServiceClient returns a client calling the instances of appName.
+func (r *Registry) NewServiceClient(appName string, opts ...ServiceClientOption) *ServiceClient {
+	c := &ServiceClient{
+		appName:     appName,
+		client:      http.DefaultClient,
+		maxAttempts: defaultServiceClientAttempts,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.cache == nil {
+		c.cache = NewRegistryCache(r, 0)
+	}
+	if c.strategy == nil {
+		c.strategy = NewRoundRobinStrategy()
+	}
+	return c
+}
+
+// Do sends a request to path on one of the UP instances of the application.
+// When the request fails with a network error or a 5xx response, it is sent
+// again to another instance, up to the configured number of attempts. The
+// response of the last attempt is returned.
+func (c *ServiceClient) Do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = ioutil.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot read request body. %v", err)
+		}
+	}
+
+	instances, err := c.cache.GetHealthyInstances(ctx, c.appName)
+	if err != nil {
+		return nil, err
+	}
+
+	tried := make(map[string]bool)
+	var lastResp *http.Response
+	var lastErr error = ErrNoInstances
+	for attempt := 0; attempt < c.maxAttempts; attempt++ {
+		candidates := make([]InstanceDetails, 0, len(instances))
+		for _, instance := range instances {
+			if !tried[instance.InstanceId] {
+				candidates = append(candidates, instance)
+			}
+		}
+		instance := c.strategy.Select(candidates)
+		if instance == nil {
+			break
+		}
+		tried[instance.InstanceId] = true
+
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+		url := instanceBaseURL(instance) + "/" + strings.TrimPrefix(path, "/")
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		if lastResp != nil {
+			lastResp.Body.Close()
+			lastResp = nil
+		}
+		resp, err := c.client.Do(req)
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if err != nil {
+			c.cache.r.logger.Printf("Request to %s FAILED. %v\n", url, err)
+			lastErr = err
+		} else {
+			c.cache.r.logger.Printf("Request to %s FAILED with status %v\n", url, resp.Status)
+			lastResp, lastErr = resp, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if lastResp != nil {
+		return lastResp, nil
+	}
+	return nil, lastErr
+}
+
+// instanceBaseURL returns the scheme, address and port to reach instance.
+func instanceBaseURL(instance *InstanceDetails) string {
+	if instance.SecurePort.Enabled == "true" {
+		return fmt.Sprintf("https://%s:%s", instance.IpAddr, instance.SecurePort.Port)
+	}
+	return fmt.Sprintf("http://%s:%s", instance.IpAddr, instance.Port.Port)
+}
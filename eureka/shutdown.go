@@ -0,0 +1,48 @@
+package eureka
+
+import (
+	"context"
+	"time"
+)
+
+// PreShutdownHook adds fn to the functions Stop calls before deregistering,
+// e.g. to drain in-flight requests, flush metrics or notify downstream
+// services while Eureka still routes traffic to the instance. The hooks are
+// called one after the other, the last added first, with a context bounded
+// by half of the time left to Stop.
+func (r *Registry) PreShutdownHook(fn func(ctx context.Context)) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.preShutdownHooks = append(r.state.preShutdownHooks, fn)
+}
+
+// runPreShutdownHooks calls the pre-shutdown hooks in LIFO order. When ctx
+// has a deadline, it stops waiting for them after half of the time left,
+// saving the rest for deregistration.
+func (r *Registry) runPreShutdownHooks(ctx context.Context) {
+	r.state.mu.Lock()
+	hooks := append(([]func(context.Context))(nil), r.state.preShutdownHooks...)
+	r.state.mu.Unlock()
+	if len(hooks) == 0 {
+		return
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Until(deadline)/2)
+		defer cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hooks[i](ctx)
+		}
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		r.logger.Println("Pre-shutdown hooks did not finish in time, deregistering anyway")
+	}
+}
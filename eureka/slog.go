@@ -0,0 +1,33 @@
+//go:build go1.21
+// +build go1.21
+
+package eureka
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// SlogLogger is a Logger writing every message at info level to a
+// *slog.Logger. It is the recommended Logger for new code.
+type SlogLogger struct {
+	l *slog.Logger
+}
+
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &SlogLogger{l: l}
+}
+
+// NewDefaultSlogLogger returns a Logger writing to slog.Default().
+func NewDefaultSlogLogger() Logger {
+	return NewSlogLogger(slog.Default())
+}
+
+func (s *SlogLogger) Printf(format string, v ...interface{}) {
+	s.l.Info(strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+}
+
+func (s *SlogLogger) Println(v ...interface{}) {
+	s.l.Info(strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
@@ -0,0 +1,116 @@
+package eureka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// InstanceStatus is the status of an instance as understood by Eureka.
+type InstanceStatus string
+
+const (
+	StatusUp           InstanceStatus = "UP"
+	StatusDown         InstanceStatus = "DOWN"
+	StatusStarting     InstanceStatus = "STARTING"
+	StatusOutOfService InstanceStatus = "OUT_OF_SERVICE"
+	StatusUnknown      InstanceStatus = "UNKNOWN"
+
+	// StatusPausing is set by Pause. It is not a Eureka status: the instance
+	// is registered OUT_OF_SERVICE while pausing.
+	StatusPausing InstanceStatus = "PAUSING"
+)
+
+// OverrideStatus places a status override for this instance in Eureka.
+// Unlike registering with a given status, the override survives heartbeats,
+// which makes it the way to take an instance OUT_OF_SERVICE without stopping
+// the heartbeat daemon.
+func (r *Registry) OverrideStatus(ctx context.Context, status InstanceStatus) error {
+	url := fmt.Sprintf("%s/apps/%s/%s/status?value=%s", r.CurrentZone(), r.AppName, r.InstanceId, status)
+
+	resp, err := r.putRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 || resp.StatusCode == 200 {
+		r.logger.Printf("Successfully override status '%s' to Eureka\n", status)
+		return nil
+	}
+	return newEurekaError("Status override", resp)
+}
+
+// DeleteStatusOverride removes the status override placed by OverrideStatus,
+// letting the status reported by the instance itself take effect again.
+func (r *Registry) DeleteStatusOverride(ctx context.Context) error {
+	url := fmt.Sprintf("%s/apps/%s/%s/status", r.CurrentZone(), r.AppName, r.InstanceId)
+
+	resp, err := r.deleteRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 || resp.StatusCode == 200 {
+		r.logger.Println("Successfully removed status override from Eureka")
+		return nil
+	}
+	return newEurekaError("Removing status override", resp)
+}
+
+const (
+	defaultOutOfServiceTimeout = 30 * time.Second
+	outOfServicePollInterval   = time.Second
+)
+
+// TakeOutOfService overrides the status of this instance to OUT_OF_SERVICE
+// and waits until Eureka no longer lists it as UP, e.g. to drain the outgoing
+// instance of a blue/green deployment before shifting traffic. The wait is
+// bounded by InitOptions.OutOfServiceTimeout.
+func (r *Registry) TakeOutOfService(ctx context.Context) error {
+	if err := r.OverrideStatus(ctx, StatusOutOfService); err != nil {
+		return err
+	}
+
+	timeout := r.opt.OutOfServiceTimeout
+	if timeout <= 0 {
+		timeout = defaultOutOfServiceTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return r.WaitUntilNotInInstances(ctx, r.AppName)
+}
+
+// WaitUntilNotInInstances polls the application until none of its UP
+// instances is this instance, or ctx is done.
+func (r *Registry) WaitUntilNotInInstances(ctx context.Context, appName string) error {
+	ticker := time.NewTicker(outOfServicePollInterval)
+	defer ticker.Stop()
+	for {
+		app, err := r.GetApplication(ctx, appName)
+		var eurekaErr *EurekaError
+		if errors.As(err, &eurekaErr) && eurekaErr.StatusCode == 404 {
+			return nil
+		}
+		if err == nil && !hasUpInstance(app, r.InstanceId) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func hasUpInstance(app *Application, instanceId string) bool {
+	for _, instance := range app.Instance {
+		if instance.InstanceId == instanceId && instance.Status == string(StatusUp) {
+			return true
+		}
+	}
+	return false
+}
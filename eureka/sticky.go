@@ -0,0 +1,68 @@
+package eureka
+
+import (
+	"hash/fnv"
+	"math"
+	"time"
+)
+
+// StickyBalancer maps each caller key, e.g. a user id, to the same UP
+// instance of an application for as long as that instance stays UP, for
+// stateful services such as WebSocket sessions. Keys are spread over the
+// instances in proportion to their advertised weight, see WeightMetadataKey,
+// and only the keys of an instance going away move to another one.
+type StickyBalancer struct {
+	r       *Registry
+	appName string
+	cache   *RegistryCache
+	weights WeightedStrategy
+}
+
+// NewStickyBalancer returns a StickyBalancer looking up the instances of
+// appName in a cache refreshed every refresh.
+func NewStickyBalancer(r *Registry, appName string, refresh time.Duration) *StickyBalancer {
+	return &StickyBalancer{
+		r:       r,
+		appName: appName,
+		cache:   NewRegistryCache(r, refresh),
+		weights: WeightedStrategy{MetadataNamespacePrefix: r.opt.MetadataNamespacePrefix},
+	}
+}
+
+// Pick returns the UP instance the caller key maps to.
+func (b *StickyBalancer) Pick(callerKey string) (*InstanceDetails, error) {
+	instances, err := b.cache.GetHealthyInstances(b.r.baseContext(), b.appName)
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) == 0 {
+		return nil, ErrNoInstances
+	}
+
+	// Weighted rendezvous hashing: the instance with the highest score for
+	// the key wins, whatever the other instances are.
+	best, bestScore := -1, math.Inf(-1)
+	for i := range instances {
+		score := b.weights.weight(&instances[i]) / -math.Log(stickyHash(callerKey, instances[i].InstanceId))
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return &instances[best], nil
+}
+
+// stickyHash hashes the pair to a number in (0, 1).
+func stickyHash(callerKey, instanceId string) float64 {
+	h := fnv.New64a()
+	h.Write([]byte(callerKey))
+	h.Write([]byte{0})
+	h.Write([]byte(instanceId))
+	// Mix the bits, FNV alone spreads similar short keys poorly.
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return (float64(x>>11) + 0.5) / (1 << 53)
+}
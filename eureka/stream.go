@@ -0,0 +1,98 @@
+package eureka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// GetApplicationsStream fetches the full registry like GetApplications, but
+// decodes the response one application at a time, sending each on the
+// channel as soon as it is parsed. Only a single application is held in
+// memory at once, which matters for registries of thousands of instances
+// processed one application at a time. Failing to fetch the registry is
+// returned; failing to decode it midway is logged and ends the stream. The
+// channel is closed once the registry has been read or ctx is done.
+func (r *Registry) GetApplicationsStream(ctx context.Context) (<-chan Application, error) {
+	url := fmt.Sprintf("%s/apps", r.CurrentZone())
+
+	resp, err := r.getRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		resp.Body.Close()
+		return nil, newEurekaError("Fetching applications", resp)
+	}
+
+	apps := make(chan Application)
+	go func() {
+		defer close(apps)
+		defer resp.Body.Close()
+		if err := decodeApplications(ctx, resp.Body, apps); err != nil && ctx.Err() == nil {
+			r.logger.Println(fmt.Errorf("Cannot unmarshal applications body. %v", err))
+		}
+	}()
+	return apps, nil
+}
+
+// decodeApplications sends every application of the registry in body to
+// apps, skipping the other fields.
+func decodeApplications(ctx context.Context, body io.Reader, apps chan<- Application) error {
+	dec := json.NewDecoder(body)
+	if err := enterObjectField(dec, "applications"); err != nil {
+		return err
+	}
+	if err := enterObjectField(dec, "application"); err != nil {
+		return err
+	}
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for dec.More() {
+		var app Application
+		if err := dec.Decode(&app); err != nil {
+			return err
+		}
+		select {
+		case apps <- app:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// enterObjectField reads the opening of an object and skips its fields up to
+// and including the key of field, leaving dec at the value of field.
+func enterObjectField(dec *json.Decoder, field string) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key == field {
+			return nil
+		}
+		var skipped json.RawMessage
+		if err := dec.Decode(&skipped); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("Missing field %q", field)
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("Expected %v, got %v", delim, token)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package eureka
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/websocket"
+)
+
+// SubscribeToChanges streams the changes to the instances of an application
+// pushed by a Eureka 2.0 server over a WebSocket at {DefaultZone}/ws/apps/
+// {appName}, each message being an InstanceEvent in JSON. When the server
+// does not accept the WebSocket, it falls back to WatchApplication polling
+// with the default interval, as it does right away in dry run mode. The
+// channel is closed once ctx is done or the server closes the WebSocket.
+func (r *Registry) SubscribeToChanges(ctx context.Context, appName string) (<-chan InstanceEvent, error) {
+	if r.opt.DryRun {
+		return r.WatchApplication(ctx, appName, 0), nil
+	}
+	ws, err := r.dialChanges(ctx, r.normalizeAppName(appName))
+	if err != nil {
+		r.logger.Printf("Cannot subscribe to changes over WebSocket, polling instead. %v\n", err)
+		return r.WatchApplication(ctx, appName, 0), nil
+	}
+
+	events := make(chan InstanceEvent)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			ws.Close()
+		case <-done:
+		}
+	}()
+	go func() {
+		defer close(events)
+		defer close(done)
+		defer ws.Close()
+		for {
+			var event InstanceEvent
+			if err := websocket.JSON.Receive(ws, &event); err != nil {
+				if ctx.Err() == nil {
+					r.logger.Println(fmt.Errorf("Change subscription to Eureka ended. %v", err))
+				}
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (r *Registry) dialChanges(ctx context.Context, appName string) (*websocket.Conn, error) {
+	endpoint, err := url.Parse(fmt.Sprintf("%s/ws/apps/%s", r.CurrentZone(), appName))
+	if err != nil {
+		return nil, err
+	}
+	origin := *endpoint
+	origin.Path = ""
+	switch endpoint.Scheme {
+	case "https":
+		endpoint.Scheme = "wss"
+	default:
+		endpoint.Scheme = "ws"
+	}
+
+	config, err := websocket.NewConfig(endpoint.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+	config.Header = http.Header{}
+	if r.Username != "" || r.Password != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte(r.Username + ":" + r.Password))
+		config.Header.Set("Authorization", "Basic "+auth)
+	}
+
+	if err := r.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	conn, err := r.dialWebSocket(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bound the handshake by ctx, then let the connection live on.
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return ws, nil
+}
+
+// dialWebSocket opens the connection to endpoint the way the HTTP client
+// would: through its dialer and DNS cache, the proxy, and with its TLS
+// config, so that the client certificate and certificate pin apply. The
+// handshake is written to the HTTP debug log, if any.
+func (r *Registry) dialWebSocket(ctx context.Context, endpoint *url.URL) (net.Conn, error) {
+	roundTripper := r.client.Transport
+	if debug, ok := roundTripper.(*debugTransport); ok {
+		debug.write("---> %s\nWebSocket handshake with %s\n\n", time.Now().Format(time.RFC3339), endpoint)
+		roundTripper = debug.next
+	}
+
+	host := endpoint.Host
+	if endpoint.Port() == "" {
+		port := "80"
+		if endpoint.Scheme == "wss" {
+			port = "443"
+		}
+		host = net.JoinHostPort(endpoint.Hostname(), port)
+	}
+
+	switch transport := roundTripper.(type) {
+	case *http.Transport:
+		conn, err := dialThroughProxy(ctx, transport, endpoint, host)
+		if err != nil {
+			return nil, err
+		}
+		if endpoint.Scheme != "wss" {
+			return conn, nil
+		}
+		tlsConfig := &tls.Config{}
+		if transport.TLSClientConfig != nil {
+			tlsConfig = transport.TLSClientConfig.Clone()
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = endpoint.Hostname()
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	case *http2.Transport:
+		// H2C: DialTLSContext opens plain TCP connections.
+		return transport.DialTLSContext(ctx, "tcp", host, nil)
+	default:
+		return nil, fmt.Errorf("Unsupported transport %T", roundTripper)
+	}
+}
+
+// dialThroughProxy dials host with the dialer of transport, tunneling
+// through the proxy transport would send a request to endpoint to.
+func dialThroughProxy(ctx context.Context, transport *http.Transport, endpoint *url.URL, host string) (net.Conn, error) {
+	dial := transport.DialContext
+	if dial == nil {
+		var dialer net.Dialer
+		dial = dialer.DialContext
+	}
+
+	var proxy *url.URL
+	if transport.Proxy != nil {
+		target := *endpoint
+		target.Scheme = strings.Replace(target.Scheme, "ws", "http", 1)
+		var err error
+		if proxy, err = transport.Proxy(&http.Request{Method: http.MethodGet, URL: &target, Header: http.Header{}}); err != nil {
+			return nil, err
+		}
+	}
+	if proxy == nil {
+		return dial(ctx, "tcp", host)
+	}
+
+	proxyHost := proxy.Host
+	if proxy.Port() == "" {
+		proxyHost = net.JoinHostPort(proxy.Hostname(), "80")
+	}
+	conn, err := dial(ctx, "tcp", proxyHost)
+	if err != nil {
+		return nil, err
+	}
+	connect := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: host},
+		Host:   host,
+		Header: http.Header{},
+	}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		connect.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	resp, err := func() (*http.Response, error) {
+		if err := connect.Write(conn); err != nil {
+			return nil, err
+		}
+		return http.ReadResponse(bufio.NewReader(conn), connect)
+	}()
+	conn.SetDeadline(time.Time{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Cannot connect through proxy %s. %v", proxy.Host, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("Cannot connect through proxy %s. %s", proxy.Host, resp.Status)
+	}
+	return conn, nil
+}
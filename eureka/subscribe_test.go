@@ -0,0 +1,47 @@
+package eureka_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"golang.org/x/net/websocket"
+)
+
+func TestSubscribeToChangesClosesWithServer(t *testing.T) {
+	ts := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		websocket.JSON.Send(ws, eureka.InstanceEvent{
+			ActionType: eureka.ActionAdded,
+			Instance:   eureka.InstanceDetails{InstanceId: "i-1"},
+		})
+	}))
+	defer ts.Close()
+
+	r, err := eureka.NewEureka(ts.URL, "APP", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := r.SubscribeToChanges(context.Background(), "OTHER")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-events:
+		if event.ActionType != eureka.ActionAdded || event.Instance.InstanceId != "i-1" {
+			t.Errorf("event = %+v, want i-1 ADDED", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("unexpected second event")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed once the server closed the WebSocket")
+	}
+}
@@ -0,0 +1,75 @@
+package eureka
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	initialThrottleBackoff = time.Second
+	maxThrottleBackoff     = time.Minute
+
+	// throttleSlowdownThreshold is how many 429 Too Many Requests in a row
+	// double the polling intervals, until the next successful fetch.
+	throttleSlowdownThreshold = 5
+)
+
+// throttled records a 429 Too Many Requests answered to a registry fetch
+// and returns how long to wait before retrying: the Retry-After of resp if
+// any, else a backoff doubling from 1 second with every 429 in a row.
+func (r *Registry) throttled(resp *http.Response) time.Duration {
+	r.state.mu.Lock()
+	r.state.throttled++
+	n := r.state.throttled
+	r.state.mu.Unlock()
+
+	if delay, ok := retryAfter(resp); ok {
+		return delay
+	}
+	delay := initialThrottleBackoff
+	for i := 1; i < n && delay < maxThrottleBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxThrottleBackoff {
+		delay = maxThrottleBackoff
+	}
+	return delay
+}
+
+// resetThrottle records a successful registry fetch.
+func (r *Registry) resetThrottle() {
+	r.state.mu.Lock()
+	r.state.throttled = 0
+	r.state.mu.Unlock()
+}
+
+// pollInterval returns interval, doubled while Eureka keeps throttling the
+// registry fetches.
+func (r *Registry) pollInterval(interval time.Duration) time.Duration {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	if r.state.throttled >= throttleSlowdownThreshold {
+		return 2 * interval
+	}
+	return interval
+}
+
+// retryAfter parses the Retry-After header of resp, given either in seconds
+// or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(at); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,126 @@
+package eureka_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"github.com/abetobing/go-eureka/server"
+)
+
+// throttlingServer answers the first throttled registry fetches with 429
+// Too Many Requests, with the given Retry-After unless empty.
+func throttlingServer(throttled int32, retryAfter string) http.Handler {
+	next := server.NewEurekaServer()
+	var fetches int32
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet && req.URL.Path == "/apps" && atomic.AddInt32(&fetches, 1) <= throttled {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func TestGetApplicationsThrottled(t *testing.T) {
+	tests := []struct {
+		name        string
+		throttled   int32
+		retryAfter  string
+		wantErr     bool
+		wantFetches int
+		minDuration time.Duration
+	}{
+		{name: "not throttled", wantFetches: 1},
+		{name: "retried after Retry-After", throttled: 2, retryAfter: "0", wantFetches: 3},
+		{name: "backoff without Retry-After", throttled: 1, wantFetches: 2, minDuration: time.Second},
+		{name: "gives up after five 429s", throttled: 100, retryAfter: "0", wantErr: true, wantFetches: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newCountingServer(throttlingServer(tt.throttled, tt.retryAfter))
+			defer ts.Close()
+
+			r, err := eureka.NewEureka(ts.zone(), "APP", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := time.Now()
+			_, err = r.GetApplications(context.Background())
+			var eurekaErr *eureka.EurekaError
+			switch {
+			case !tt.wantErr && err != nil:
+				t.Fatalf("GetApplications() error = %v", err)
+			case tt.wantErr && (!errors.As(err, &eurekaErr) || eurekaErr.StatusCode != http.StatusTooManyRequests):
+				t.Fatalf("GetApplications() error = %v, want a 429 EurekaError", err)
+			}
+			if fetches := ts.count(); fetches != tt.wantFetches {
+				t.Errorf("%d fetches, want %d", fetches, tt.wantFetches)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minDuration {
+				t.Errorf("returned after %v, want at least %v", elapsed, tt.minDuration)
+			}
+		})
+	}
+}
+
+func TestGetApplicationsThrottledStopsWithContext(t *testing.T) {
+	ts := newCountingServer(throttlingServer(100, "60"))
+	defer ts.Close()
+
+	r, err := eureka.NewEureka(ts.zone(), "APP", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := r.GetApplications(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetApplications() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRegistrySummariesThrottled(t *testing.T) {
+	tests := []struct {
+		name  string
+		fetch func(r *eureka.Registry) error
+	}{
+		{
+			name: "GetApplicationNames",
+			fetch: func(r *eureka.Registry) error {
+				_, err := r.GetApplicationNames(context.Background())
+				return err
+			},
+		},
+		{
+			name: "GetAllInstanceIDs",
+			fetch: func(r *eureka.Registry) error {
+				_, err := r.GetAllInstanceIDs(context.Background())
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newCountingServer(throttlingServer(2, "0"))
+			defer ts.Close()
+
+			r, err := eureka.NewEureka(ts.zone(), "APP", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.fetch(r); err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if fetches := ts.count(); fetches != 3 {
+				t.Errorf("%d fetches, want 3", fetches)
+			}
+		})
+	}
+}
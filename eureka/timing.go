@@ -0,0 +1,67 @@
+package eureka
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WithTimingLogger calls fn after every request to Eureka with the name of
+// the operation, e.g. "register", "heartbeat", "deregister" or
+// "getApplication", and how long it took until the response headers were
+// received or the request failed. It is meant to feed StatsD or a custom
+// dashboard, e.g. to find out why registration is slow.
+func WithTimingLogger(fn func(op string, duration time.Duration)) Option {
+	return func(r *Registry) error {
+		r.timingLogger = fn
+		return nil
+	}
+}
+
+// recordTiming reports the request to path, relative to the Eureka server
+// URL, started at start to the timing logger, if any.
+func (r *Registry) recordTiming(method, path string, start time.Time) {
+	if r.timingLogger == nil {
+		return
+	}
+	r.timingLogger(operationName(method, path), time.Since(start))
+}
+
+// operationName tells the operation of a request to Eureka from its method
+// and path, falling back to the lowercased method for requests it does not
+// know.
+func operationName(method, path string) string {
+	if u, err := url.Parse(path); err == nil {
+		path = u.Path
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch segments[0] {
+	case "apps":
+		switch {
+		case len(segments) == 1:
+			return "getApplications"
+		case len(segments) == 2 && method == "POST":
+			return "register"
+		case len(segments) == 2:
+			return "getApplication"
+		case len(segments) == 3 && method == "PUT":
+			return "heartbeat"
+		case len(segments) == 3 && method == "DELETE":
+			return "deregister"
+		case len(segments) == 3:
+			return "getInstance"
+		case segments[3] == "status" && method == "DELETE":
+			return "deleteStatusOverride"
+		case segments[3] == "status":
+			return "updateStatus"
+		case segments[3] == "metadata":
+			return "updateMetadata"
+		}
+	case "instances":
+		return "getInstance"
+	case "info":
+		return "getServerInfo"
+	}
+	return strings.ToLower(method)
+}
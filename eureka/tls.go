@@ -0,0 +1,200 @@
+package eureka
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FIPSCipherSuites returns the TLS 1.2 cipher suites approved by FIPS 140-2,
+// the ones used when InitOptions.FIPSCompliant is set. TLS 1.3 suites are
+// not configurable in crypto/tls and are all AES-GCM or ChaCha20 based.
+func FIPSCipherSuites() []uint16 {
+	return []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	}
+}
+
+// LoadRootCAsFromFile returns the system cert pool with the PEM encoded
+// certificates of certFile added, for InitOptions.RootCAs.
+func LoadRootCAsFromFile(certFile string) (*x509.CertPool, error) {
+	pool := systemCertPool()
+	if err := appendCertsFromFile(pool, certFile); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// LoadRootCAsFromDir returns the system cert pool with the PEM encoded
+// certificates of every .pem, .crt and .cer file of dir added, for
+// InitOptions.RootCAs.
+func LoadRootCAsFromDir(dir string) (*x509.CertPool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read CA directory %s. %v", dir, err)
+	}
+	pool := systemCertPool()
+	found := false
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".pem", ".crt", ".cer":
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		if err := appendCertsFromFile(pool, filepath.Join(dir, entry.Name())); err != nil {
+			return nil, err
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("No certificate found in %s", dir)
+	}
+	return pool, nil
+}
+
+func systemCertPool() *x509.CertPool {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		return x509.NewCertPool()
+	}
+	return pool
+}
+
+func appendCertsFromFile(pool *x509.CertPool, certFile string) error {
+	pem, err := os.ReadFile(certFile)
+	if err != nil {
+		return fmt.Errorf("Cannot read CA file %s. %v", certFile, err)
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("No certificate found in %s", certFile)
+	}
+	return nil
+}
+
+// clientTLSConfig builds the TLS config for connections to Eureka, nil when
+// the defaults of crypto/tls apply.
+func (r *Registry) clientTLSConfig() (*tls.Config, error) {
+	cert, err := r.clientCertificate()
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.TLSConfig == nil && !r.opt.FIPSCompliant && r.opt.RootCAs == nil && cert == nil && r.opt.ServerCertFingerprint == "" {
+		return nil, nil
+	}
+	config := &tls.Config{}
+	if r.opt.TLSConfig != nil {
+		config = r.opt.TLSConfig.Clone()
+	}
+	if r.opt.RootCAs != nil {
+		config.RootCAs = r.opt.RootCAs
+	}
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+	if r.opt.FIPSCompliant {
+		config.MinVersion = tls.VersionTLS12
+		config.CipherSuites = FIPSCipherSuites()
+		config.CurvePreferences = []tls.CurveID{
+			tls.CurveP256,
+			tls.CurveP384,
+			tls.CurveP521,
+		}
+	}
+	if r.opt.ServerCertFingerprint != "" {
+		pinCertificate(config, r.opt.ServerCertFingerprint)
+	}
+	return config, nil
+}
+
+// pinCertificate makes config accept only the server certificate with the
+// given SHA-256 fingerprint. The pin replaces verification against the
+// root CAs, so that a self-signed certificate can be pinned; a config
+// verifying its own way keeps doing so as well.
+func pinCertificate(config *tls.Config, fingerprint string) {
+	want := normalizeFingerprint(fingerprint)
+	verify := config.VerifyConnection
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return ErrCertPinMismatch
+		}
+		sum := sha256.Sum256(state.PeerCertificates[0].Raw)
+		if hex.EncodeToString(sum[:]) != want {
+			return ErrCertPinMismatch
+		}
+		if verify != nil {
+			return verify(state)
+		}
+		return nil
+	}
+}
+
+// unpinCertificate undoes pinCertificate on config, restoring the
+// verification of original, the config it was built from, if any.
+func unpinCertificate(config, original *tls.Config) {
+	config.InsecureSkipVerify = false
+	config.VerifyConnection = nil
+	if original != nil {
+		config.InsecureSkipVerify = original.InsecureSkipVerify
+		config.VerifyConnection = original.VerifyConnection
+	}
+}
+
+// normalizeFingerprint lowercases a hex fingerprint and drops the colons
+// of the "AB:CD:..." notation.
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+}
+
+// validFingerprint tells whether fingerprint is a SHA-256 hex fingerprint.
+func validFingerprint(fingerprint string) bool {
+	b, err := hex.DecodeString(normalizeFingerprint(fingerprint))
+	return err == nil && len(b) == sha256.Size
+}
+
+// LoadClientCertFromEnv returns the client certificate whose PEM encoded
+// certificate chain and private key are the values of the EUREKA_CLIENT_CERT
+// and EUREKA_CLIENT_KEY environment variables, for
+// InitOptions.ClientCertificate.
+func LoadClientCertFromEnv() (*tls.Certificate, error) {
+	certPEM := os.Getenv("EUREKA_CLIENT_CERT")
+	keyPEM := os.Getenv("EUREKA_CLIENT_KEY")
+	if certPEM == "" || keyPEM == "" {
+		return nil, errors.New("EUREKA_CLIENT_CERT and EUREKA_CLIENT_KEY must both be set")
+	}
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("Cannot load client certificate from environment. %v", err)
+	}
+	return &cert, nil
+}
+
+// clientCertificate returns the certificate to authenticate to Eureka with,
+// nil if none is configured. It fails when ClientCertFile and ClientKeyFile
+// cannot be loaded, rather than going on without a client certificate and
+// failing the TLS handshake with Eureka later.
+func (r *Registry) clientCertificate() (*tls.Certificate, error) {
+	if r.opt.ClientCertificate != nil {
+		return r.opt.ClientCertificate, nil
+	}
+	if r.opt.ClientCertFile == "" || r.opt.ClientKeyFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.opt.ClientCertFile, r.opt.ClientKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot load client certificate. %v", err)
+	}
+	return &cert, nil
+}
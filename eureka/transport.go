@@ -0,0 +1,91 @@
+package eureka
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// newHTTPClient builds the HTTP client used for all calls to Eureka.
+//
+// Keep-alive connections idle between heartbeats can be dropped silently by a
+// firewall or NAT, in which case a request sent over one hangs until TCP gives
+// up. TCP keep-alive probes more frequent than the usual NAT idle timeouts
+// keep such connections alive, and SendHeartbeat times out when one went
+// stale anyway.
+func (r *Registry) newHTTPClient() (*http.Client, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 15 * time.Second,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	if proxy, err := url.Parse(r.opt.ProxyURL); err == nil && proxy.Host != "" {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	tlsConfig, err := r.clientTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+	transport.ExpectContinueTimeout = time.Second
+	transport.ResponseHeaderTimeout = r.opt.ResponseHeaderTimeout
+
+	ttl := r.opt.DNSCacheTTL
+	if ttl == 0 {
+		ttl = defaultDNSCacheTTL
+	}
+	if ttl > 0 {
+		transport.DialContext = newDNSCacheDialer(dialer, ttl).DialContext
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if r.opt.H2C {
+		roundTripper = newH2CTransport(transport.DialContext)
+	}
+	if r.httpDebugLog != nil {
+		roundTripper = &debugTransport{next: roundTripper, w: r.httpDebugLog}
+	}
+
+	return &http.Client{Transport: roundTripper, CheckRedirect: checkRedirect}, nil
+}
+
+// HTTPClient returns a copy of the HTTP client the registry calls Eureka
+// with, e.g. to call the instances discovered through
+// WithServiceHTTPClient. The copy has its own clone of the transport, with
+// the same dialer, DNS cache, proxy and TLS config, so changing it does not
+// affect the registry. The settings only meant for Eureka are left out: the
+// credentials, the ServerCertFingerprint pin, the redirect policy and the
+// HTTP debug log. With H2C, the HTTP/2 transport is shared instead.
+func (r *Registry) HTTPClient() *http.Client {
+	client := *r.client
+	client.CheckRedirect = nil
+	if debug, ok := client.Transport.(*debugTransport); ok {
+		client.Transport = debug.next
+	}
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		transport = transport.Clone()
+		if transport.TLSClientConfig != nil && r.opt.ServerCertFingerprint != "" {
+			unpinCertificate(transport.TLSClientConfig, r.opt.TLSConfig)
+		}
+		client.Transport = transport
+	}
+	return &client
+}
+
+// newH2CTransport returns a transport speaking HTTP/2 with prior knowledge
+// over the plain TCP connections of dial.
+func newH2CTransport(dial func(ctx context.Context, network, addr string) (net.Conn, error)) *http2.Transport {
+	return &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dial(ctx, network, addr)
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package eureka_test
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abetobing/go-eureka/eureka"
+)
+
+func TestHTTPClientDropsServerCertPin(t *testing.T) {
+	service := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer service.Close()
+
+	// The pin of a Eureka server other than the service.
+	sum := sha256.Sum256([]byte("eureka"))
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(service.Certificate())
+	r, err := eureka.NewEureka("https://eureka.test/eureka", "APP", &eureka.InitOptions{
+		ServerCertFingerprint: hex.EncodeToString(sum[:]),
+		RootCAs:               rootCAs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := r.HTTPClient()
+	if client.CheckRedirect != nil {
+		t.Error("CheckRedirect of the Eureka client kept")
+	}
+	resp, err := client.Get(service.URL)
+	if err != nil {
+		t.Fatalf("calling a service trusted by RootCAs failed: %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,81 @@
+package eureka
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ValidateConfig checks the registry configuration, so that a misconfigured
+// service fails at startup instead of retrying registration forever. It is
+// called by Register and Start, and can be called earlier by the caller.
+// Every problem found is reported at once, joined with errors.Join.
+func (r *Registry) ValidateConfig() error {
+	var errs []error
+	zone, err := url.Parse(r.DefaultZone)
+	switch {
+	case err != nil:
+		errs = append(errs, fmt.Errorf("Invalid Eureka server URL %q. %v", r.DefaultZone, err))
+	case zone.Scheme != "http" && zone.Scheme != "https":
+		errs = append(errs, fmt.Errorf("Invalid Eureka server URL %q. Scheme must be http or https", r.DefaultZone))
+	case zone.Host == "":
+		errs = append(errs, fmt.Errorf("Invalid Eureka server URL %q. Host is empty", r.DefaultZone))
+	case r.opt.H2C && (zone.Scheme != "http" || r.opt.TLSConfig != nil):
+		errs = append(errs, fmt.Errorf("Invalid Eureka server URL %q. h2c requires http without a TLS config", r.DefaultZone))
+	}
+	if r.opt.ProxyURL != "" {
+		if proxy, err := url.Parse(r.opt.ProxyURL); err != nil || proxy.Host == "" {
+			errs = append(errs, fmt.Errorf("Invalid proxy URL %q", r.opt.ProxyURL))
+		} else if r.opt.H2C {
+			errs = append(errs, fmt.Errorf("Proxy URL %q cannot be combined with h2c", r.opt.ProxyURL))
+		}
+	}
+	if strings.TrimSpace(r.AppName) == "" {
+		errs = append(errs, fmt.Errorf("App name is empty"))
+	} else if !r.validAppName(r.AppName) {
+		errs = append(errs, fmt.Errorf("Invalid app name %q. App name must consist of uppercase letters, digits, underscores and hyphens", r.AppName))
+	}
+	if !validPort(r.Port) {
+		errs = append(errs, fmt.Errorf("Invalid port %q. Port must be a number between 1 and 65535", r.Port))
+	}
+	if r.opt.SecurePort != "" && !validPort(r.opt.SecurePort) {
+		errs = append(errs, fmt.Errorf("Invalid secure port %q. Port must be a number between 1 and 65535", r.opt.SecurePort))
+	}
+	if r.opt.ServerCertFingerprint != "" && !validFingerprint(r.opt.ServerCertFingerprint) {
+		errs = append(errs, fmt.Errorf("Invalid server certificate fingerprint %q. Fingerprint must be a SHA-256 hash in hex", r.opt.ServerCertFingerprint))
+	}
+	if _, err := r.clientCertificate(); err != nil {
+		errs = append(errs, err)
+	}
+	if hasControlCharacter(r.Username) || hasControlCharacter(r.Password) {
+		errs = append(errs, fmt.Errorf("Eureka credentials contain control characters"))
+	}
+	return errors.Join(errs...)
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+// validAppName tells whether appName follows the Eureka naming convention.
+// Hyphens are allowed, being common in the app names of Spring Cloud
+// services, and so are lowercase letters when PreserveAppNameCase is set.
+func (r *Registry) validAppName(appName string) bool {
+	for _, c := range appName {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-':
+		case c >= 'a' && c <= 'z' && r.opt.PreserveAppNameCase:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func hasControlCharacter(s string) bool {
+	return strings.IndexFunc(s, unicode.IsControl) >= 0
+}
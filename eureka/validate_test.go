@@ -0,0 +1,115 @@
+package eureka_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abetobing/go-eureka/eureka"
+)
+
+func TestValidateConfig(t *testing.T) {
+	const zone = "http://eureka.test/eureka"
+	tests := []struct {
+		name    string
+		zone    string
+		appName string
+		opt     eureka.InitOptions
+		wantErr []string
+	}{
+		{name: "valid", opt: eureka.InitOptions{Port: "8080"}},
+		{name: "hyphenated app name", appName: "my-app", opt: eureka.InitOptions{Port: "8080"}},
+		{
+			name:    "lowercase app name kept",
+			appName: "my-app",
+			opt:     eureka.InitOptions{Port: "8080", PreserveAppNameCase: true},
+		},
+		{
+			name:    "scheme",
+			zone:    "ftp://eureka.test/eureka",
+			opt:     eureka.InitOptions{Port: "8080"},
+			wantErr: []string{"Scheme must be http or https"},
+		},
+		{
+			name:    "empty host",
+			zone:    "http:///eureka",
+			opt:     eureka.InitOptions{Port: "8080"},
+			wantErr: []string{"Host is empty"},
+		},
+		{
+			name:    "h2c over https",
+			zone:    "https://eureka.test/eureka",
+			opt:     eureka.InitOptions{Port: "8080", H2C: true},
+			wantErr: []string{"h2c requires http"},
+		},
+		{
+			name:    "proxy",
+			opt:     eureka.InitOptions{Port: "8080", ProxyURL: "not a proxy"},
+			wantErr: []string{"Invalid proxy URL"},
+		},
+		{
+			name:    "app name",
+			appName: "my app",
+			opt:     eureka.InitOptions{Port: "8080"},
+			wantErr: []string{"Invalid app name"},
+		},
+		{
+			name:    "port",
+			opt:     eureka.InitOptions{Port: "65536"},
+			wantErr: []string{`Invalid port "65536"`},
+		},
+		{
+			name:    "secure port",
+			opt:     eureka.InitOptions{Port: "8080", SecurePort: "https"},
+			wantErr: []string{`Invalid secure port "https"`},
+		},
+		{
+			name:    "fingerprint",
+			opt:     eureka.InitOptions{Port: "8080", ServerCertFingerprint: "AB:CD"},
+			wantErr: []string{"Invalid server certificate fingerprint"},
+		},
+		{
+			name:    "credentials",
+			opt:     eureka.InitOptions{Port: "8080", Username: "admin\n"},
+			wantErr: []string{"control characters"},
+		},
+		{
+			name:    "every problem reported",
+			zone:    "ftp://eureka.test/eureka",
+			appName: "my app",
+			opt:     eureka.InitOptions{Port: "0"},
+			wantErr: []string{"Scheme must be http or https", "Invalid app name", `Invalid port "0"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.zone == "" {
+				tt.zone = zone
+			}
+			if tt.appName == "" {
+				tt.appName = "APP"
+			}
+			r, err := eureka.NewEureka(tt.zone, tt.appName, &tt.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = r.ValidateConfig()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateConfig() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ValidateConfig() = nil, want %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("ValidateConfig() = %v, want it to contain %q", err, want)
+				}
+			}
+			if got := strings.Count(err.Error(), "\n") + 1; got != len(tt.wantErr) {
+				t.Errorf("ValidateConfig() reported %d problems, want %d: %v", got, len(tt.wantErr), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package eureka
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ActionType tells how an instance changed, using Eureka's names.
+type ActionType string
+
+const (
+	ActionAdded    ActionType = "ADDED"
+	ActionModified ActionType = "MODIFIED"
+	ActionDeleted  ActionType = "DELETED"
+)
+
+// InstanceEvent is a change to a single instance of an application.
+type InstanceEvent struct {
+	ActionType ActionType      `json:"actionType"`
+	Instance   InstanceDetails `json:"instance"`
+}
+
+const defaultWatchInterval = 30 * time.Second
+
+// WatchApplication polls the application every interval (30 seconds when
+// zero) and sends an event for every instance added, modified or deleted
+// since the previous poll. The instances found by the first poll are sent as
+// ADDED. An application that is not registered counts as having no
+// instances; other failures are logged and the next poll tries again. The
+// channel is closed once ctx is done.
+func (r *Registry) WatchApplication(ctx context.Context, appName string, interval time.Duration) <-chan InstanceEvent {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	events := make(chan InstanceEvent)
+	go func() {
+		defer close(events)
+
+		var known map[string]InstanceDetails
+		for {
+			current, err := r.applicationInstances(ctx, appName)
+			if err != nil {
+				r.logger.Printf("Cannot watch application %s. %v\n", appName, err)
+			} else {
+				for _, event := range diffInstances(known, current) {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+				known = current
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(r.pollInterval(interval)):
+			}
+		}
+	}()
+	return events
+}
+
+// WatchAllApplications polls the full registry every interval (30 seconds
+// when zero) and calls handler with the events for every instance of any
+// application added, modified or deleted since the previous poll. The
+// instances found by the first poll are reported as ADDED. Failed polls are
+// logged and the next poll tries again. It blocks until ctx is done, then
+// returns ctx.Err().
+func (r *Registry) WatchAllApplications(ctx context.Context, interval time.Duration, handler func(events []InstanceEvent)) error {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	var known map[string]InstanceDetails
+	for {
+		apps, err := r.GetApplications(ctx)
+		if err != nil {
+			r.logger.Printf("Cannot watch applications. %v\n", err)
+		} else {
+			current := make(map[string]InstanceDetails)
+			for _, app := range apps.Application {
+				for _, instance := range app.Instance {
+					current[instance.InstanceId] = instance
+				}
+			}
+			if events := diffInstances(known, current); len(events) > 0 {
+				handler(events)
+			}
+			known = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.pollInterval(interval)):
+		}
+	}
+}
+
+// applicationInstances returns the instances of the application keyed by
+// instance id, none if the application is not registered.
+func (r *Registry) applicationInstances(ctx context.Context, appName string) (map[string]InstanceDetails, error) {
+	app, err := r.GetApplication(ctx, appName)
+	var eurekaErr *EurekaError
+	if errors.As(err, &eurekaErr) && eurekaErr.StatusCode == 404 {
+		return map[string]InstanceDetails{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	instances := make(map[string]InstanceDetails, len(app.Instance))
+	for _, instance := range app.Instance {
+		instances[instance.InstanceId] = instance
+	}
+	return instances, nil
+}
+
+// diffInstances returns the events turning the instances in prev into the
+// ones in cur, both keyed by instance id.
+func diffInstances(prev, cur map[string]InstanceDetails) []InstanceEvent {
+	var events []InstanceEvent
+	for id, instance := range cur {
+		old, ok := prev[id]
+		switch {
+		case !ok:
+			events = append(events, InstanceEvent{ActionType: ActionAdded, Instance: instance})
+		case !reflect.DeepEqual(old, instance):
+			events = append(events, InstanceEvent{ActionType: ActionModified, Instance: instance})
+		}
+	}
+	for id, instance := range prev {
+		if _, ok := cur[id]; !ok {
+			events = append(events, InstanceEvent{ActionType: ActionDeleted, Instance: instance})
+		}
+	}
+	return events
+}
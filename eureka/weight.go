@@ -0,0 +1,69 @@
+package eureka
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+// WeightMetadataKey is the metadata key an instance advertises its weight
+// under, a positive number defaulting to 1. It is registered from
+// InitOptions.InstanceWeight, but can also be set like any other metadata.
+const WeightMetadataKey = "eureka.weight"
+
+// WeightedStrategy picks a candidate at random with a probability
+// proportional to its advertised weight. When all candidates weigh the same,
+// it cycles through them like RoundRobinStrategy.
+type WeightedStrategy struct {
+	// MetadataNamespacePrefix is the prefix the instances registered their
+	// metadata keys with, if any.
+	MetadataNamespacePrefix string
+
+	roundRobin RoundRobinStrategy
+}
+
+func NewWeightedStrategy() *WeightedStrategy {
+	return &WeightedStrategy{}
+}
+
+func (s *WeightedStrategy) Select(instances []InstanceDetails) *InstanceDetails {
+	if len(instances) == 0 {
+		return nil
+	}
+	weights := make([]float64, len(instances))
+	total := 0.0
+	equal := true
+	for i := range instances {
+		weights[i] = s.weight(&instances[i])
+		total += weights[i]
+		equal = equal && weights[i] == weights[0]
+	}
+	if equal {
+		return s.roundRobin.Select(instances)
+	}
+
+	n := rand.Float64() * total
+	for i := range instances {
+		n -= weights[i]
+		if n < 0 {
+			return &instances[i]
+		}
+	}
+	return &instances[len(instances)-1]
+}
+
+// weight returns the advertised weight of instance, 1 when it is missing or
+// invalid.
+func (s *WeightedStrategy) weight(instance *InstanceDetails) float64 {
+	w, err := strconv.ParseFloat(instance.Metadata[s.MetadataNamespacePrefix+WeightMetadataKey], 64)
+	if err != nil || !validWeight(w) {
+		return 1
+	}
+	return w
+}
+
+// validWeight tells whether w is a positive, finite weight. ParseFloat
+// accepts "NaN" and "Inf", either of which would break the selection.
+func validWeight(w float64) bool {
+	return w > 0 && !math.IsInf(w, 0)
+}
@@ -0,0 +1,42 @@
+package eureka_test
+
+import (
+	"testing"
+
+	"github.com/abetobing/go-eureka/eureka"
+)
+
+func TestWeightedStrategyInvalidWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []string
+	}{
+		{name: "missing", weights: []string{"", "", ""}},
+		{name: "not a number", weights: []string{"heavy", "1", ""}},
+		{name: "not positive", weights: []string{"0", "-2", "1"}},
+		{name: "NaN", weights: []string{"NaN", "1", ""}},
+		{name: "infinite", weights: []string{"Inf", "-Inf", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instances := make([]eureka.InstanceDetails, len(tt.weights))
+			for i, w := range tt.weights {
+				instances[i].InstanceId = string(rune('a' + i))
+				if w != "" {
+					instances[i].Metadata = map[string]string{eureka.WeightMetadataKey: w}
+				}
+			}
+
+			// Invalid weights count as 1, so every instance weighs the
+			// same and gets picked in turn.
+			s := eureka.NewWeightedStrategy()
+			picked := make(map[string]bool)
+			for range instances {
+				picked[s.Select(instances).InstanceId] = true
+			}
+			if len(picked) != len(instances) {
+				t.Errorf("picked %v out of %d instances, want each of them once", picked, len(instances))
+			}
+		})
+	}
+}
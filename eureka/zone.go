@@ -0,0 +1,46 @@
+package eureka
+
+import "sort"
+
+const zoneMetadataKey = "availabilityZone"
+
+// instanceZone returns the availability zone instance registered with, from
+// its metadata or else its Amazon data center info.
+func (r *Registry) instanceZone(instance *InstanceDetails) string {
+	if zone := r.instanceMetadata(instance)[zoneMetadataKey]; zone != "" {
+		return zone
+	}
+	if metadata := instance.DataCenterInfo.Metadata; metadata != nil {
+		return metadata.AvailabilityZone
+	}
+	return ""
+}
+
+// preferSameZone reports whether InitOptions.PreferSameZone applies, which
+// takes a SelfZone to compare instances with.
+func (r *Registry) preferSameZone() bool {
+	return r.opt.PreferSameZone && r.opt.SelfZone != ""
+}
+
+// sortBySameZone moves the instances in the zone of the registry first,
+// keeping the order of the instances otherwise.
+func (r *Registry) sortBySameZone(instances []InstanceDetails) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		return r.instanceZone(&instances[i]) == r.opt.SelfZone && r.instanceZone(&instances[j]) != r.opt.SelfZone
+	})
+}
+
+// sameZoneInstances returns the instances in the zone of the registry, or
+// all of them when there is none.
+func (r *Registry) sameZoneInstances(instances []InstanceDetails) []InstanceDetails {
+	var same []InstanceDetails
+	for _, instance := range instances {
+		if r.instanceZone(&instance) == r.opt.SelfZone {
+			same = append(same, instance)
+		}
+	}
+	if len(same) == 0 {
+		return instances
+	}
+	return same
+}
@@ -0,0 +1,139 @@
+package eureka_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"github.com/abetobing/go-eureka/server"
+)
+
+// countingServer is a fake Eureka server serving under /eureka, counting
+// the requests it receives.
+type countingServer struct {
+	*httptest.Server
+	requests int32
+}
+
+func newCountingServer(handler http.Handler) *countingServer {
+	s := &countingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+		http.StripPrefix("/eureka", handler).ServeHTTP(w, req)
+	}))
+	return s
+}
+
+func (s *countingServer) zone() string { return s.URL + "/eureka" }
+
+func (s *countingServer) count() int { return int(atomic.LoadInt32(&s.requests)) }
+
+// deadZone returns the URL of a Eureka server no longer listening.
+func deadZone() string {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+	return ts.URL + "/eureka"
+}
+
+func TestZoneFailover(t *testing.T) {
+	live := newCountingServer(server.NewEurekaServer())
+	defer live.Close()
+	dead1, dead2 := deadZone(), deadZone()
+
+	tests := []struct {
+		name         string
+		defaultZone  string
+		defaultZones []string
+		wantErr      bool
+		wantZone     string
+	}{
+		{
+			name:         "first zone up",
+			defaultZone:  live.zone(),
+			defaultZones: []string{live.zone(), dead1},
+			wantZone:     live.zone(),
+		},
+		{
+			name:         "fails over to the next zone",
+			defaultZone:  dead1,
+			defaultZones: []string{dead1, live.zone()},
+			wantZone:     live.zone(),
+		},
+		{
+			name:         "fails over past several zones",
+			defaultZone:  dead1,
+			defaultZones: []string{dead1, dead2, live.zone()},
+			wantZone:     live.zone(),
+		},
+		{
+			name:         "wraps around",
+			defaultZone:  dead2,
+			defaultZones: []string{live.zone(), dead1, dead2},
+			wantZone:     live.zone(),
+		},
+		{
+			name:         "every zone down",
+			defaultZone:  dead1,
+			defaultZones: []string{dead1, dead2},
+			wantErr:      true,
+			wantZone:     dead2,
+		},
+		{
+			name:        "no other zone",
+			defaultZone: dead1,
+			wantErr:     true,
+			wantZone:    dead1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := eureka.NewEureka(tt.defaultZone, "APP", &eureka.InitOptions{
+				Port:         "8080",
+				DefaultZones: tt.defaultZones,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = r.GetApplications(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetApplications() error = %v, want error %v", err, tt.wantErr)
+			}
+			if zone := r.CurrentZone(); zone != tt.wantZone {
+				t.Errorf("CurrentZone() = %q, want %q", zone, tt.wantZone)
+			}
+			if r.DefaultZone != tt.defaultZone {
+				t.Errorf("DefaultZone changed to %q", r.DefaultZone)
+			}
+		})
+	}
+}
+
+func TestZoneFailoverSticks(t *testing.T) {
+	live := newCountingServer(server.NewEurekaServer())
+	defer live.Close()
+	dead := deadZone()
+
+	r, err := eureka.NewEureka(dead, "APP", &eureka.InitOptions{
+		Port:         "8080",
+		DefaultZones: []string{dead, live.zone()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop(ctx)
+
+	// Every later request, built from CurrentZone, goes to the live zone.
+	if _, err := r.GetInstanceByID(ctx, "APP", r.InstanceId); err != nil {
+		t.Fatalf("instance not registered to the live zone: %v", err)
+	}
+	if zone := r.CurrentZone(); zone != live.zone() {
+		t.Errorf("CurrentZone() = %q, want %q", zone, live.zone())
+	}
+}
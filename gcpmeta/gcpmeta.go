@@ -0,0 +1,95 @@
+// Package gcpmeta populates Eureka registrations with the instance details
+// published by the GCP metadata server, for services running on Compute
+// Engine, GKE or Cloud Run.
+package gcpmeta
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abetobing/go-eureka/eureka"
+)
+
+const (
+	defaultMetadataHost = "metadata.google.internal"
+
+	// registrationTimeout bounds the metadata lookup done by WithGCPDataCenter.
+	registrationTimeout = 2 * time.Second
+)
+
+var client = &http.Client{Transport: &http.Transport{Proxy: nil}}
+
+// CollectGCPMetadata queries the GCP metadata server for the zone, project,
+// instance id and host name of the running instance. It returns a
+// DataCenterInfo named "GCP" and the collected values as instance metadata.
+//
+// The metadata server host can be overridden with the GCE_METADATA_HOST
+// environment variable, as with the official GCP client libraries.
+func CollectGCPMetadata(ctx context.Context) (eureka.DataCenterInfo, map[string]string, error) {
+	paths := map[string]string{
+		"zone":        "instance/zone",
+		"project-id":  "project/project-id",
+		"instance-id": "instance/id",
+		"hostname":    "instance/hostname",
+	}
+
+	metadata := make(map[string]string, len(paths))
+	for key, path := range paths {
+		value, err := get(ctx, path)
+		if err != nil {
+			return eureka.DataCenterInfo{}, nil, err
+		}
+		metadata[key] = value
+	}
+	// The zone comes as "projects/{number}/zones/{zone}".
+	metadata["zone"] = metadata["zone"][strings.LastIndex(metadata["zone"], "/")+1:]
+
+	dataCenterInfo := eureka.NewMyOwnDataCenterInfo()
+	dataCenterInfo.Name = "GCP"
+	return dataCenterInfo, metadata, nil
+}
+
+// WithGCPDataCenter makes the registry collect its data center info from the
+// GCP metadata server at registration time. The lookup is bounded to two
+// seconds; when it fails the registry falls back to the MyOwn data center.
+func WithGCPDataCenter() eureka.Option {
+	return eureka.WithDataCenterProvider(func(ctx context.Context) (eureka.DataCenterInfo, map[string]string, error) {
+		ctx, cancel := context.WithTimeout(ctx, registrationTimeout)
+		defer cancel()
+		return CollectGCPMetadata(ctx)
+	})
+}
+
+func get(ctx context.Context, path string) (string, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = defaultMetadataHost
+	}
+	url := fmt.Sprintf("http://%s/computeMetadata/v1/%s", host, path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Cannot reach GCP metadata server. %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("Fetching GCP metadata %s FAILED with status %v", path, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
@@ -1,5 +1,14 @@
 module github.com/abetobing/go-eureka
 
-go 1.14
+go 1.20
 
-require github.com/google/uuid v1.1.2
+require (
+	github.com/google/uuid v1.1.2
+	golang.org/x/crypto v0.14.0
+	golang.org/x/net v0.17.0
+	golang.org/x/sync v0.1.0
+	golang.org/x/time v0.3.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/text v0.13.0 // indirect
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/abetobing/go-eureka/eureka"
+	"golang.org/x/sync/errgroup"
+)
+
+// ReplicationAction is what a replicated change does to an instance, using
+// the names of the Netflix Eureka replication protocol.
+type ReplicationAction string
+
+const (
+	ActionHeartbeat            ReplicationAction = "Heartbeat"
+	ActionRegister             ReplicationAction = "Register"
+	ActionCancel               ReplicationAction = "Cancel"
+	ActionStatusUpdate         ReplicationAction = "StatusUpdate"
+	ActionDeleteStatusOverride ReplicationAction = "DeleteStatusOverride"
+)
+
+// ReplicationInstance is a single change replicated to a peer.
+type ReplicationInstance struct {
+	AppName            string                  `json:"appName"`
+	Id                 string                  `json:"id"`
+	LastDirtyTimestamp int64                   `json:"lastDirtyTimestamp,omitempty"`
+	OverriddenStatus   string                  `json:"overriddenStatus,omitempty"`
+	Status             string                  `json:"status,omitempty"`
+	InstanceInfo       *eureka.InstanceDetails `json:"instanceInfo,omitempty"`
+	Action             ReplicationAction       `json:"action"`
+}
+
+// ReplicationList is the body of a batch replication request.
+type ReplicationList struct {
+	ReplicationList []ReplicationInstance `json:"replicationList"`
+}
+
+// ReplicationInstanceResponse is the outcome of a single replicated change.
+type ReplicationInstanceResponse struct {
+	StatusCode     int                     `json:"statusCode"`
+	ResponseEntity *eureka.InstanceDetails `json:"responseEntity,omitempty"`
+}
+
+// ReplicationListResponse is the body of a batch replication response, with
+// one response per replicated change, in order.
+type ReplicationListResponse struct {
+	ResponseList []ReplicationInstanceResponse `json:"responseList"`
+}
+
+// ReplicationClient replicates instance changes to Eureka peers in batches,
+// through POST {peer}/peerreplication/batch/.
+type ReplicationClient struct {
+	PeerUrls []string
+	Username string
+	Password string
+	Client   *http.Client
+}
+
+func NewReplicationClient(peerUrls ...string) *ReplicationClient {
+	return &ReplicationClient{PeerUrls: peerUrls, Client: http.DefaultClient}
+}
+
+// Replicate sends the batch to every peer concurrently. The first error from
+// any peer is returned, but the other peers still receive the batch. The
+// outcome of each change is not checked; use ReplicateTo for it.
+func (c *ReplicationClient) Replicate(ctx context.Context, batch []ReplicationInstance) error {
+	var g errgroup.Group
+	for _, peerUrl := range c.PeerUrls {
+		peerUrl := peerUrl
+		g.Go(func() error {
+			_, err := c.ReplicateTo(ctx, peerUrl, batch)
+			return err
+		})
+	}
+	return g.Wait()
+}
+
+// ReplicateTo sends the batch to a single peer and returns the outcome of
+// each change.
+func (c *ReplicationClient) ReplicateTo(ctx context.Context, peerUrl string, batch []ReplicationInstance) ([]ReplicationInstanceResponse, error) {
+	body, err := json.Marshal(ReplicationList{ReplicationList: batch})
+	if err != nil {
+		return nil, fmt.Errorf("Cannot marshal replication body. %v", err)
+	}
+
+	url := fmt.Sprintf("%s/peerreplication/batch/", strings.TrimSuffix(peerUrl, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-netflix-discovery-replication", "true")
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Replication to %s FAILED with status %v", peerUrl, resp.Status)
+	}
+
+	var result ReplicationListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("Cannot unmarshal replication response body. %v", err)
+	}
+	return result.ResponseList, nil
+}
+
+// replicate applies a batch of replicated changes, each one the way the
+// regular endpoint of its action does.
+func (s *EurekaServer) replicate(w http.ResponseWriter, req *http.Request) {
+	var batch ReplicationList
+	if err := json.NewDecoder(req.Body).Decode(&batch); err != nil {
+		http.Error(w, fmt.Sprintf("Cannot unmarshal replication body. %v", err), http.StatusBadRequest)
+		return
+	}
+
+	result := ReplicationListResponse{ResponseList: []ReplicationInstanceResponse{}}
+	for _, item := range batch.ReplicationList {
+		result.ResponseList = append(result.ResponseList, ReplicationInstanceResponse{StatusCode: s.applyReplication(item)})
+	}
+	writeJSON(w, result)
+}
+
+func (s *EurekaServer) applyReplication(item ReplicationInstance) int {
+	switch item.Action {
+	case ActionRegister:
+		if item.InstanceInfo == nil {
+			return http.StatusBadRequest
+		}
+		return s.registerInstance(item.AppName, *item.InstanceInfo)
+	case ActionHeartbeat:
+		return s.renewInstance(item.AppName, item.Id)
+	case ActionCancel:
+		return s.cancelInstance(item.AppName, item.Id)
+	case ActionStatusUpdate:
+		return s.setStatusOverride(item.AppName, item.Id, item.Status)
+	case ActionDeleteStatusOverride:
+		return s.clearStatusOverride(item.AppName, item.Id)
+	default:
+		return http.StatusBadRequest
+	}
+}
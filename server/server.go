@@ -0,0 +1,347 @@
+// Package server implements a lightweight, in-memory subset of the Eureka
+// REST API. It is primarily meant as test infrastructure for the eureka
+// client, but is also usable for embedding a simplified Eureka server in
+// all-in-one binaries or offline development environments.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abetobing/go-eureka/eureka"
+)
+
+// EurekaServer is an in-memory Eureka server. State is kept in maps guarded
+// by a RWMutex and is lost when the process exits.
+type EurekaServer struct {
+	mu        sync.RWMutex
+	apps      map[string]map[string]eureka.InstanceDetails
+	overrides map[string]string
+	started   time.Time
+	Verbose   bool
+}
+
+func NewEurekaServer() *EurekaServer {
+	return &EurekaServer{
+		apps:      make(map[string]map[string]eureka.InstanceDetails),
+		overrides: make(map[string]string),
+		started:   time.Now(),
+	}
+}
+
+// ServeHTTP serves the Eureka REST API. Paths are accepted both with and
+// without the conventional "/eureka" prefix, so the server can be mounted at
+// the root of an http.Server or httptest.Server.
+func (s *EurekaServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if s.Verbose {
+		log.Printf("%s %s\n", req.Method, req.URL)
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/eureka")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) == 2 && parts[0] == "instances" && req.Method == http.MethodGet {
+		s.getInstanceGlobal(w, parts[1])
+		return
+	}
+	if len(parts) == 2 && parts[0] == "peerreplication" && parts[1] == "batch" && req.Method == http.MethodPost {
+		s.replicate(w, req)
+		return
+	}
+	if len(parts) == 1 && parts[0] == "info" && req.Method == http.MethodGet {
+		s.getInfo(w)
+		return
+	}
+	if len(parts) == 0 || parts[0] != "apps" {
+		http.NotFound(w, req)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && req.Method == http.MethodGet:
+		s.getApplications(w)
+	case len(parts) == 2 && req.Method == http.MethodGet:
+		s.getApplication(w, parts[1])
+	case len(parts) == 2 && req.Method == http.MethodPost:
+		s.register(w, req, parts[1])
+	case len(parts) == 3 && req.Method == http.MethodGet:
+		s.getInstance(w, parts[1], parts[2])
+	case len(parts) == 3 && req.Method == http.MethodPut:
+		s.heartbeat(w, parts[1], parts[2])
+	case len(parts) == 3 && req.Method == http.MethodDelete:
+		s.deregister(w, parts[1], parts[2])
+	case len(parts) == 4 && parts[3] == "status" && req.Method == http.MethodPut:
+		s.overrideStatus(w, parts[1], parts[2], req.URL.Query().Get("value"))
+	case len(parts) == 4 && parts[3] == "status" && req.Method == http.MethodDelete:
+		s.deleteStatusOverride(w, parts[1], parts[2])
+	case len(parts) == 4 && parts[3] == "metadata" && req.Method == http.MethodPut:
+		s.updateMetadata(w, req, parts[1], parts[2])
+	default:
+		http.Error(w, fmt.Sprintf("Unsupported %s %s", req.Method, req.URL.Path), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *EurekaServer) register(w http.ResponseWriter, req *http.Request, appName string) {
+	var body eureka.RequestBody
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(w, fmt.Sprintf("Cannot unmarshal instance body. %v", err), http.StatusBadRequest)
+		return
+	}
+	writeStatus(w, s.registerInstance(appName, body.Instance))
+}
+
+func (s *EurekaServer) heartbeat(w http.ResponseWriter, appName, instanceId string) {
+	writeStatus(w, s.renewInstance(appName, instanceId))
+}
+
+func (s *EurekaServer) deregister(w http.ResponseWriter, appName, instanceId string) {
+	writeStatus(w, s.cancelInstance(appName, instanceId))
+}
+
+func (s *EurekaServer) overrideStatus(w http.ResponseWriter, appName, instanceId, status string) {
+	if status == "" {
+		http.Error(w, "Missing status value", http.StatusBadRequest)
+		return
+	}
+	writeStatus(w, s.setStatusOverride(appName, instanceId, status))
+}
+
+func (s *EurekaServer) deleteStatusOverride(w http.ResponseWriter, appName, instanceId string) {
+	writeStatus(w, s.clearStatusOverride(appName, instanceId))
+}
+
+// writeStatus answers with the status code of a change to the registry.
+func writeStatus(w http.ResponseWriter, code int) {
+	if code == http.StatusNotFound {
+		http.NotFound(w, nil)
+		return
+	}
+	w.WriteHeader(code)
+}
+
+// The changes to the registry below return the status code to answer with,
+// so that the regular endpoints and peer replication share them.
+
+func (s *EurekaServer) registerInstance(appName string, instance eureka.InstanceDetails) int {
+	if instance.InstanceId == "" {
+		instance.InstanceId = instance.HostName
+	}
+	appName = strings.ToUpper(appName)
+	instance.App = appName
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Of two conflicting registrations, the most recently changed one wins.
+	if existing, ok := s.apps[appName][instance.InstanceId]; ok && existing.LastDirtyTimestamp > instance.LastDirtyTimestamp {
+		return http.StatusNoContent
+	}
+	if override, ok := s.overrides[instance.InstanceId]; ok {
+		instance.Status = override
+		instance.OverriddenStatus = &override
+	}
+	if s.apps[appName] == nil {
+		s.apps[appName] = make(map[string]eureka.InstanceDetails)
+	}
+	s.apps[appName][instance.InstanceId] = instance
+	return http.StatusNoContent
+}
+
+func (s *EurekaServer) renewInstance(appName, instanceId string) int {
+	s.mu.RLock()
+	_, ok := s.apps[strings.ToUpper(appName)][instanceId]
+	s.mu.RUnlock()
+	if !ok {
+		return http.StatusNotFound
+	}
+	return http.StatusOK
+}
+
+func (s *EurekaServer) cancelInstance(appName, instanceId string) int {
+	appName = strings.ToUpper(appName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.apps[appName][instanceId]; !ok {
+		return http.StatusNotFound
+	}
+	delete(s.apps[appName], instanceId)
+	delete(s.overrides, instanceId)
+	if len(s.apps[appName]) == 0 {
+		delete(s.apps, appName)
+	}
+	return http.StatusOK
+}
+
+func (s *EurekaServer) setStatusOverride(appName, instanceId, status string) int {
+	if status == "" {
+		return http.StatusBadRequest
+	}
+	appName = strings.ToUpper(appName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	instance, ok := s.apps[appName][instanceId]
+	if !ok {
+		return http.StatusNotFound
+	}
+	s.overrides[instanceId] = status
+	instance.Status = status
+	instance.OverriddenStatus = &status
+	s.apps[appName][instanceId] = instance
+	return http.StatusOK
+}
+
+func (s *EurekaServer) clearStatusOverride(appName, instanceId string) int {
+	appName = strings.ToUpper(appName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	instance, ok := s.apps[appName][instanceId]
+	if !ok {
+		return http.StatusNotFound
+	}
+	delete(s.overrides, instanceId)
+	instance.Status = string(eureka.StatusUnknown)
+	instance.OverriddenStatus = nil
+	s.apps[appName][instanceId] = instance
+	return http.StatusOK
+}
+
+func (s *EurekaServer) updateMetadata(w http.ResponseWriter, req *http.Request, appName, instanceId string) {
+	appName = strings.ToUpper(appName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	instance, ok := s.apps[appName][instanceId]
+	if !ok {
+		http.NotFound(w, nil)
+		return
+	}
+	metadata := make(map[string]string, len(instance.Metadata))
+	for k, v := range instance.Metadata {
+		metadata[k] = v
+	}
+	for k, v := range req.URL.Query() {
+		metadata[k] = v[0]
+	}
+	instance.Metadata = metadata
+	s.apps[appName][instanceId] = instance
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *EurekaServer) getApplications(w http.ResponseWriter) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.apps))
+	for name := range s.apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	apps := eureka.Applications{VersionsDelta: "1", Application: []eureka.Application{}}
+	for _, name := range names {
+		apps.Application = append(apps.Application, s.application(name))
+	}
+	apps.AppsHashcode = s.appsHashcode()
+	writeJSON(w, eureka.ApplicationsResponse{Applications: apps})
+}
+
+func (s *EurekaServer) getApplication(w http.ResponseWriter, appName string) {
+	appName = strings.ToUpper(appName)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.apps[appName]; !ok {
+		http.NotFound(w, nil)
+		return
+	}
+	writeJSON(w, eureka.ApplicationResponse{Application: s.application(appName)})
+}
+
+func (s *EurekaServer) getInstance(w http.ResponseWriter, appName, instanceId string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	instance, ok := s.apps[strings.ToUpper(appName)][instanceId]
+	if !ok {
+		http.NotFound(w, nil)
+		return
+	}
+	writeJSON(w, eureka.InstanceResponse{Instance: instance})
+}
+
+func (s *EurekaServer) getInstanceGlobal(w http.ResponseWriter, instanceId string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, instances := range s.apps {
+		if instance, ok := instances[instanceId]; ok {
+			writeJSON(w, eureka.InstanceResponse{Instance: instance})
+			return
+		}
+	}
+	http.NotFound(w, nil)
+}
+
+func (s *EurekaServer) getInfo(w http.ResponseWriter) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	instances := 0
+	for _, app := range s.apps {
+		instances += len(app)
+	}
+	writeJSON(w, map[string]interface{}{
+		"name":         "go-eureka",
+		"started":      s.started.UTC().Format(time.RFC3339),
+		"uptime":       time.Since(s.started).Round(time.Second).String(),
+		"applications": len(s.apps),
+		"instances":    instances,
+	})
+}
+
+// application builds the Application for appName. The caller must hold s.mu.
+func (s *EurekaServer) application(appName string) eureka.Application {
+	ids := make([]string, 0, len(s.apps[appName]))
+	for id := range s.apps[appName] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	app := eureka.Application{Name: appName, Instance: []eureka.InstanceDetails{}}
+	for _, id := range ids {
+		app.Instance = append(app.Instance, s.apps[appName][id])
+	}
+	return app
+}
+
+// appsHashcode computes the Eureka apps hashcode, e.g. "DOWN_1_UP_2_".
+// The caller must hold s.mu.
+func (s *EurekaServer) appsHashcode() string {
+	counts := make(map[string]int)
+	for _, instances := range s.apps {
+		for _, instance := range instances {
+			counts[instance.Status]++
+		}
+	}
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var b strings.Builder
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "%s_%d_", status, counts[status])
+	}
+	return b.String()
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println(fmt.Errorf("Cannot marshal response body. %v", err))
+	}
+}
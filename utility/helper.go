@@ -1,11 +1,63 @@
 package utility
 
 import (
+	"context"
 	"errors"
 	"net"
+	"time"
 )
 
+// DefaultExternalIPTimeout is how long ExternalIP waits for the network.
+const DefaultExternalIPTimeout = 2 * time.Second
+
+// ExternalIPProbeAddr is the address ExternalIP dials a UDP connection to,
+// which sends nothing, to find the interface routing outbound traffic. Any
+// address outside the local networks would do.
+const ExternalIPProbeAddr = "8.8.8.8:80"
+
+// ExternalIP is ExternalIPWithTimeout with DefaultExternalIPTimeout.
 func ExternalIP() (string, error) {
+	return ExternalIPWithTimeout(DefaultExternalIPTimeout)
+}
+
+// ExternalIPWithTimeout returns the IPv4 address of the interface routing
+// outbound traffic to ExternalIPProbeAddr. Without a route it falls back to
+// the address of the first external interface that is up. The whole lookup
+// is bounded by timeout, after which it fails with
+// context.DeadlineExceeded and is left to finish in the background.
+func ExternalIPWithTimeout(timeout time.Duration) (string, error) {
+	type result struct {
+		ip  string
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ip, err := externalIP()
+		done <- result{ip, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case res := <-done:
+		return res.ip, res.err
+	case <-timer.C:
+		return "", context.DeadlineExceeded
+	}
+}
+
+func externalIP() (string, error) {
+	conn, err := net.Dial("udp4", ExternalIPProbeAddr)
+	if err == nil {
+		defer conn.Close()
+		if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok && !addr.IP.IsLoopback() && !addr.IP.IsUnspecified() {
+			return addr.IP.String(), nil
+		}
+	}
+	return interfaceIP()
+}
+
+func interfaceIP() (string, error) {
 	ifaces, err := net.Interfaces()
 	if err != nil {
 		return "", err